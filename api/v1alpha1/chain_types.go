@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -46,6 +47,12 @@ type ChainSpec struct {
 	// +optional
 	MinerType string `json:"minerType,omitempty"`
 
+	// MinerLabels are set on the genesis miner, and on its pod when the pod is created.
+	// Changes are applied to an existing genesis miner but not to its running pod.
+	// Labels managed by the controller, such as the chain name label, cannot be overridden.
+	// +optional
+	MinerLabels map[string]string `json:"minerLabels,omitempty"`
+
 	// Image is the blockchain node image.
 	// +kubebuilder:validation:MinLength=1
 	Image string `json:"image"`
@@ -57,6 +64,39 @@ type ChainSpec struct {
 	// BootstrapAccount is the bootstrap account (will be auto-generated).
 	// +optional
 	BootstrapAccount *string `json:"bootstrapAccount,omitempty"`
+
+	// ImagePullSecrets is a list of references to secrets in the same namespace used
+	// to pull the images of all miners belonging to the chain.
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// ExtraConfig is additional configuration merged into the chain ConfigMap.
+	// Keys managed by the controller, such as chainName and image, cannot be overridden.
+	// +optional
+	ExtraConfig map[string]string `json:"extraConfig,omitempty"`
+
+	// WorkerReplicas is the number of worker miners of the chain. When set, the controller
+	// manages a MinerSet for the workers in addition to the genesis miner.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	WorkerReplicas *int32 `json:"workerReplicas,omitempty"`
+
+	// WorkerTemplate describes the worker miners. Its ChainName is always set to the chain.
+	// Defaults to a miner of the chain's MinerType.
+	// +optional
+	WorkerTemplate *MinerTemplateSpec `json:"workerTemplate,omitempty"`
+
+	// ConfigMapName is the name of an existing ConfigMap in the chain namespace that
+	// holds the chain configuration. When set, the controller consumes this ConfigMap
+	// instead of creating its own, and ExtraConfig is ignored.
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	ConfigMapName *string `json:"configMapName,omitempty"`
+
+	// Paused freezes the chain, for example during maintenance. While paused, the controller
+	// does not create or update the chain resources but keeps the status current.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
 }
 
 // ChainStatus defines the observed state of Chain
@@ -69,6 +109,28 @@ type ChainStatus struct {
 	// +optional
 	MinerRef *LocalObjectReference `json:"minerRef,omitempty"`
 
+	// MinerSetRef points to the MinerSet of the chain's worker miners.
+	// +optional
+	MinerSetRef *LocalObjectReference `json:"minerSetRef,omitempty"`
+
+	// FailureReason will be set in the event that there is a terminal problem
+	// reconciling the chain and categorizes that problem.
+	// +optional
+	FailureReason *string `json:"failureReason,omitempty"`
+
+	// FailureMessage will be set in the event that there is a terminal problem
+	// reconciling the chain and contains a human readable description of it.
+	// +optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+
+	// MinerCount is the number of miners belonging to the chain, the genesis miner and workers included.
+	// +optional
+	MinerCount int32 `json:"minerCount,omitempty"`
+
+	// ReadyMinerCount is the number of miners belonging to the chain that are ready.
+	// +optional
+	ReadyMinerCount int32 `json:"readyMinerCount,omitempty"`
+
 	// ObservedGeneration is the latest generation observed by the controller.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
@@ -81,7 +143,11 @@ type ChainStatus struct {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Miners",type="integer",JSONPath=".status.minerCount"
+// +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyMinerCount"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Chain is the Schema for the chains API
 type Chain struct {
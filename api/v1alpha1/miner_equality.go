@@ -0,0 +1,55 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// MinerSpecSemanticEqual reports whether two miner specs are semantically equal once the
+// fields defaulted by the API server and the webhook are filled in, so that an empty
+// RestartPolicy equals Always, a nil PodDeletionTimeout equals the default timeout and
+// empty Resources equal the default resource profile of the miner type.
+func MinerSpecSemanticEqual(a, b MinerSpec) bool {
+	return equality.Semantic.DeepEqual(normalizeMinerSpec(a), normalizeMinerSpec(b))
+}
+
+// normalizeMinerSpec returns a copy of spec with its defaulted fields set.
+func normalizeMinerSpec(spec MinerSpec) *MinerSpec {
+	out := spec.DeepCopy()
+	if out.ContainerName == "" {
+		out.ContainerName = "miner"
+	}
+	if out.RestartPolicy == "" {
+		out.RestartPolicy = corev1.RestartPolicyAlways
+	}
+	if out.ManagePod == nil {
+		out.ManagePod = ptr.To(true)
+	}
+	if out.PodDeletionTimeout == nil {
+		out.PodDeletionTimeout = &metav1.Duration{Duration: DefaultMinerPodDeletionTimeout}
+	}
+	if equality.Semantic.DeepEqual(out.Resources, corev1.ResourceRequirements{}) {
+		if profile, ok := DefaultMinerResourceProfiles()[out.MinerType]; ok {
+			out.Resources = profile
+		}
+	}
+	return out
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMinerSpecSemanticEqual(t *testing.T) {
+	base := MinerSpec{ChainName: "chain", MinerType: MinerTypeSmall}
+
+	tests := []struct {
+		name   string
+		mutate func(spec *MinerSpec)
+		equal  bool
+	}{
+		{
+			name:   "identical specs",
+			mutate: func(*MinerSpec) {},
+			equal:  true,
+		},
+		{
+			name:   "defaulted restart policy",
+			mutate: func(spec *MinerSpec) { spec.RestartPolicy = corev1.RestartPolicyAlways },
+			equal:  true,
+		},
+		{
+			name:   "different restart policy",
+			mutate: func(spec *MinerSpec) { spec.RestartPolicy = corev1.RestartPolicyNever },
+			equal:  false,
+		},
+		{
+			name: "defaulted pod deletion timeout",
+			mutate: func(spec *MinerSpec) {
+				spec.PodDeletionTimeout = &metav1.Duration{Duration: DefaultMinerPodDeletionTimeout}
+			},
+			equal: true,
+		},
+		{
+			name:   "different pod deletion timeout",
+			mutate: func(spec *MinerSpec) { spec.PodDeletionTimeout = &metav1.Duration{Duration: time.Minute} },
+			equal:  false,
+		},
+		{
+			name: "webhook defaulted resources",
+			mutate: func(spec *MinerSpec) {
+				spec.Resources = DefaultMinerResourceProfiles()[MinerTypeSmall]
+			},
+			equal: true,
+		},
+		{
+			name: "resources other than the profile",
+			mutate: func(spec *MinerSpec) {
+				spec.Resources = DefaultMinerResourceProfiles()[MinerTypeLarge]
+			},
+			equal: false,
+		},
+		{
+			name:   "different miner type",
+			mutate: func(spec *MinerSpec) { spec.MinerType = MinerTypeLarge },
+			equal:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			other := *base.DeepCopy()
+			tt.mutate(&other)
+			g.Expect(MinerSpecSemanticEqual(base, other)).To(Equal(tt.equal))
+			g.Expect(MinerSpecSemanticEqual(other, base)).To(Equal(tt.equal))
+		})
+	}
+}
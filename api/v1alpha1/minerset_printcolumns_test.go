@@ -0,0 +1,107 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+const (
+	minerSetCRDPath            = "../../config/crd/bases/apps.onex.io_minersets.yaml"
+	minerSetPrinterColumnsPath = "testdata/minerset_printer_columns.golden.yaml"
+)
+
+// loadMinerSetCRD reads the generated MinerSet CustomResourceDefinition.
+func loadMinerSetCRD(t *testing.T) *apiextensionsv1.CustomResourceDefinition {
+	t.Helper()
+
+	raw, err := os.ReadFile(filepath.FromSlash(minerSetCRDPath))
+	if err != nil {
+		t.Fatalf("failed to read the MinerSet CRD: %v", err)
+	}
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := yaml.UnmarshalStrict(raw, crd); err != nil {
+		t.Fatalf("failed to decode the MinerSet CRD: %v", err)
+	}
+	return crd
+}
+
+// TestMinerSetPrinterColumns compares the kubectl columns of every MinerSet version with
+// the golden file. Run with -update to accept a change of the printcolumn markers.
+func TestMinerSetPrinterColumns(t *testing.T) {
+	g := NewWithT(t)
+
+	columns := map[string][]apiextensionsv1.CustomResourceColumnDefinition{}
+	for _, version := range loadMinerSetCRD(t).Spec.Versions {
+		columns[version.Name] = version.AdditionalPrinterColumns
+	}
+	got, err := yaml.Marshal(columns)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	if *updateGolden {
+		g.Expect(os.WriteFile(filepath.FromSlash(minerSetPrinterColumnsPath), got, 0o644)).To(Succeed())
+	}
+	want, err := os.ReadFile(filepath.FromSlash(minerSetPrinterColumnsPath))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(got)).To(Equal(string(want)))
+}
+
+// TestMinerSetPrinterColumnsReadyCondition evaluates the column JSONPaths against a
+// MinerSet, to check that they pick the MinersReady entry out of the conditions list.
+func TestMinerSetPrinterColumnsReadyCondition(t *testing.T) {
+	g := NewWithT(t)
+
+	ms := &MinerSet{
+		Status: MinerSetStatus{
+			Conditions: []metav1.Condition{
+				{Type: "MinersCreated", Status: metav1.ConditionTrue, Reason: "Created"},
+				{Type: "MinersReady", Status: metav1.ConditionFalse, Reason: "Unavailable"},
+				{Type: "Resized", Status: metav1.ConditionTrue, Reason: "Resized"},
+			},
+		},
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ms)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	want := map[string]string{"Ready": "False", "Reason": "Unavailable"}
+	for _, version := range loadMinerSetCRD(t).Spec.Versions {
+		for _, column := range version.AdditionalPrinterColumns {
+			expected, ok := want[column.Name]
+			if !ok {
+				continue
+			}
+			parser := jsonpath.New(column.Name)
+			g.Expect(parser.Parse("{"+column.JSONPath+"}")).To(Succeed(), "column %s of %s", column.Name, version.Name)
+			var sb strings.Builder
+			g.Expect(parser.Execute(&sb, obj)).To(Succeed(), "column %s of %s", column.Name, version.Name)
+			g.Expect(sb.String()).To(Equal(expected), "column %s of %s", column.Name, version.Name)
+		}
+	}
+}
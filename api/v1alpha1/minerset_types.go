@@ -18,12 +18,21 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 const (
 	// MinerSetFinalizer is the finalizer used by the MinerSet controller to
 	// clean up referenced template resources if necessary when a MinerSet is being deleted.
 	MinerSetFinalizer = "minerset.onex.io/finalizer"
+
+	// DefaultMinerSetReplicas is the number of replicas of a MinerSet that does not
+	// specify any.
+	DefaultMinerSetReplicas int32 = 1
+
+	// DefaultMinerSetProgressDeadlineSeconds is the progress deadline of a MinerSet that
+	// does not specify any.
+	DefaultMinerSetProgressDeadlineSeconds int32 = 600
 )
 
 // MinerTemplateSpec defines the miner template
@@ -61,11 +70,29 @@ const (
 
 	// DeletePolicyOldest deletes the oldest pod.
 	DeletePolicyOldest DeletePolicy = "Oldest"
+
+	// DeletePolicyUnhealthyFirst deletes the least healthy pod, ranked by its phase,
+	// readiness and container restarts.
+	DeletePolicyUnhealthyFirst DeletePolicy = "UnhealthyFirst"
+)
+
+// MinerNamingStrategy defines how a MinerSet names the miners it creates.
+type MinerNamingStrategy string
+
+const (
+	// MinerNamingStrategyGenerateName names miners after the MinerSet with a random suffix.
+	MinerNamingStrategyGenerateName MinerNamingStrategy = "GenerateName"
+
+	// MinerNamingStrategyOrdinal names miners after the MinerSet with the lowest free
+	// ordinal, from <minerset>-0 to <minerset>-<replicas-1>.
+	MinerNamingStrategyOrdinal MinerNamingStrategy = "Ordinal"
 )
 
 // MinerSetSpec defines the desired state of MinerSet
 type MinerSetSpec struct {
-	// Replicas is the number of desired replicas.
+	// Replicas is the number of desired replicas. An unset value is defaulted on
+	// admission, to DefaultMinerSetReplicas unless configured otherwise, and zero
+	// scales the MinerSet down to no miners.
 	// +kubebuilder:validation:Minimum=0
 	// +optional
 	Replicas *int32 `json:"replicas,omitempty"`
@@ -85,10 +112,17 @@ type MinerSetSpec struct {
 
 	// DeletePolicy defines the delete policy for the pods when a MinerSet scales down.
 	// Default to Random.
-	// +kubebuilder:validation:Enum=Random;Newest;Oldest
+	// +kubebuilder:validation:Enum=Random;Newest;Oldest;UnhealthyFirst
 	// +optional
 	DeletePolicy DeletePolicy `json:"deletePolicy,omitempty"`
 
+	// NamingStrategy defines how the MinerSet names the miners it creates. Ordinal gives
+	// miners stable names that are reused once a scale down frees them.
+	// Default to GenerateName.
+	// +kubebuilder:validation:Enum=GenerateName;Ordinal
+	// +optional
+	NamingStrategy MinerNamingStrategy `json:"namingStrategy,omitempty"`
+
 	// MinReadySeconds is the minimum number of seconds for which a newly created pod should
 	// be ready without any of its container crashing, for it to be considered available.
 	// Defaults to 0.
@@ -97,10 +131,66 @@ type MinerSetSpec struct {
 
 	// ProgressDeadlineSeconds is the maximum duration in seconds that a MinerSet may take
 	// to progress, before it is considered to be failed.
-	// Defaults to 600 seconds.
+	// Defaults to DefaultMinerSetProgressDeadlineSeconds on admission.
 	// +kubebuilder:validation:Minimum=1
 	// +optional
 	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	// MaxProvisioning is the maximum number of miners that can be provisioning at the same
+	// time, counting the miners that have not reached the Running or Failed phase yet.
+	// Scale-ups create more miners as earlier ones start running. No limit applies when unset.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxProvisioning *int32 `json:"maxProvisioning,omitempty"`
+
+	// WaitForChainReady makes the MinerSet wait until the Chain referenced by the
+	// template's ChainName has created its ConfigMap before creating any miners.
+	// Defaults to false.
+	// +optional
+	WaitForChainReady bool `json:"waitForChainReady,omitempty"`
+
+	// HeadlessService makes the MinerSet create a headless Service, named after the
+	// MinerSet, that selects its miners so that they can discover their peers over DNS.
+	// Defaults to false.
+	// +optional
+	HeadlessService bool `json:"headlessService,omitempty"`
+
+	// AdoptOrphans defines whether the MinerSet adopts miners without a controller that
+	// match its selector. When false, such miners are left alone.
+	// Defaults to true.
+	// +kubebuilder:default=true
+	// +optional
+	AdoptOrphans *bool `json:"adoptOrphans,omitempty"`
+
+	// PodDisruptionBudget makes the MinerSet create a PodDisruptionBudget, named after
+	// the MinerSet, that protects its miners from voluntary disruptions such as node drains.
+	// +optional
+	PodDisruptionBudget *MinerSetPodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+}
+
+// MinerSetPodDisruptionBudget describes the PodDisruptionBudget of a MinerSet's miners.
+// At most one of MinAvailable and MaxUnavailable can be set.
+// +kubebuilder:validation:XValidation:rule="!(has(self.minAvailable) && has(self.maxUnavailable))",message="minAvailable and maxUnavailable are mutually exclusive"
+type MinerSetPodDisruptionBudget struct {
+	// MinAvailable is the number or percentage of miners that must remain available during
+	// an eviction. An absolute number is capped to the MinerSet replicas.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// MaxUnavailable is the number or percentage of miners that can be unavailable during
+	// an eviction.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// UnavailableMiner identifies a miner of a MinerSet that is not ready.
+type UnavailableMiner struct {
+	// Name of the miner.
+	Name string `json:"name"`
+
+	// Phase is the current phase of the miner.
+	// +optional
+	Phase MinerPhase `json:"phase,omitempty"`
 }
 
 // MinerSetStatus defines the observed state of MinerSet
@@ -121,6 +211,20 @@ type MinerSetStatus struct {
 	// +optional
 	AvailableReplicas int32 `json:"availableReplicas,omitempty"`
 
+	// UnavailableMiners lists the miners that are not ready, sorted by name and capped
+	// to a small number of entries, to help finding problem miners.
+	// +optional
+	UnavailableMiners []UnavailableMiner `json:"unavailableMiners,omitempty"`
+
+	// ServiceRef points to the headless Service of the MinerSet's miners.
+	// +optional
+	ServiceRef *LocalObjectReference `json:"serviceRef,omitempty"`
+
+	// ObservedTemplateHash is the hash of the miner template the controller last acted on.
+	// Miners created from it carry the same hash in their template hash annotation.
+	// +optional
+	ObservedTemplateHash string `json:"observedTemplateHash,omitempty"`
+
 	// ObservedGeneration is the latest generation observed by the controller.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
@@ -143,7 +247,11 @@ type MinerSetStatus struct {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="MinersReady")].status`
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=`.status.conditions[?(@.type=="MinersReady")].reason`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // MinerSet is the Schema for the minersets API
 type MinerSet struct {
@@ -0,0 +1,81 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	"k8s.io/apimachinery/pkg/api/apitesting/roundtrip"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/utils/ptr"
+)
+
+// TestRoundTripTypes fuzzes every kind of the group version and checks that it survives
+// a JSON round trip unchanged, which catches mistakes in the field tags.
+func TestRoundTripTypes(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(AddToScheme(scheme)).To(Succeed())
+	codecs := serializer.NewCodecFactory(scheme)
+	seed := rand.Int63()
+	t.Logf("fuzzing with seed %d", seed)
+	f := fuzzer.FuzzerFor(metafuzzer.Funcs, rand.NewSource(seed), codecs)
+
+	for _, kind := range []string{"Chain", "ChainList", "Miner", "MinerList", "MinerSet", "MinerSetList"} {
+		t.Run(kind, func(t *testing.T) {
+			roundtrip.RoundTripSpecificKindWithoutProtobuf(t, GroupVersion.WithKind(kind), scheme, codecs, f, nil)
+		})
+	}
+}
+
+// TestRoundTripZeroPointers checks that pointer fields set to their zero value are not
+// dropped, since for them unset and zero mean different things.
+func TestRoundTripZeroPointers(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		obj     any
+		decoded any
+	}{
+		{
+			obj:     &MinerSet{Spec: MinerSetSpec{Replicas: ptr.To[int32](0), ProgressDeadlineSeconds: ptr.To[int32](0)}},
+			decoded: &MinerSet{},
+		},
+		{
+			obj:     &Miner{Spec: MinerSpec{PodDeletionTimeout: &metav1.Duration{}, ManagePod: ptr.To(false)}},
+			decoded: &Miner{},
+		},
+		{
+			obj:     &Chain{Spec: ChainSpec{BootstrapAccount: ptr.To(""), WorkerReplicas: ptr.To[int32](0)}},
+			decoded: &Chain{},
+		},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.obj)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(json.Unmarshal(data, tt.decoded)).To(Succeed())
+		g.Expect(tt.decoded).To(Equal(tt.obj), "JSON: %s", data)
+	}
+}
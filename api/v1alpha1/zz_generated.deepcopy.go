@@ -21,9 +21,10 @@ limitations under the License.
 package v1alpha1
 
 import (
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -88,11 +89,45 @@ func (in *ChainList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChainSpec) DeepCopyInto(out *ChainSpec) {
 	*out = *in
+	if in.MinerLabels != nil {
+		in, out := &in.MinerLabels, &out.MinerLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.BootstrapAccount != nil {
 		in, out := &in.BootstrapAccount, &out.BootstrapAccount
 		*out = new(string)
 		**out = **in
 	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtraConfig != nil {
+		in, out := &in.ExtraConfig, &out.ExtraConfig
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.WorkerReplicas != nil {
+		in, out := &in.WorkerReplicas, &out.WorkerReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WorkerTemplate != nil {
+		in, out := &in.WorkerTemplate, &out.WorkerTemplate
+		*out = new(MinerTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigMapName != nil {
+		in, out := &in.ConfigMapName, &out.ConfigMapName
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChainSpec.
@@ -118,9 +153,24 @@ func (in *ChainStatus) DeepCopyInto(out *ChainStatus) {
 		*out = new(LocalObjectReference)
 		**out = **in
 	}
+	if in.MinerSetRef != nil {
+		in, out := &in.MinerSetRef, &out.MinerSetRef
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(string)
+		**out = **in
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -179,6 +229,21 @@ func (in *Miner) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MinerContainerStatus) DeepCopyInto(out *MinerContainerStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MinerContainerStatus.
+func (in *MinerContainerStatus) DeepCopy() *MinerContainerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MinerContainerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MinerList) DeepCopyInto(out *MinerList) {
 	*out = *in
@@ -270,6 +335,31 @@ func (in *MinerSetList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MinerSetPodDisruptionBudget) DeepCopyInto(out *MinerSetPodDisruptionBudget) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MinerSetPodDisruptionBudget.
+func (in *MinerSetPodDisruptionBudget) DeepCopy() *MinerSetPodDisruptionBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(MinerSetPodDisruptionBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MinerSetSpec) DeepCopyInto(out *MinerSetSpec) {
 	*out = *in
@@ -285,6 +375,21 @@ func (in *MinerSetSpec) DeepCopyInto(out *MinerSetSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.MaxProvisioning != nil {
+		in, out := &in.MaxProvisioning, &out.MaxProvisioning
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AdoptOrphans != nil {
+		in, out := &in.AdoptOrphans, &out.AdoptOrphans
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(MinerSetPodDisruptionBudget)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MinerSetSpec.
@@ -300,6 +405,16 @@ func (in *MinerSetSpec) DeepCopy() *MinerSetSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MinerSetStatus) DeepCopyInto(out *MinerSetStatus) {
 	*out = *in
+	if in.UnavailableMiners != nil {
+		in, out := &in.UnavailableMiners, &out.UnavailableMiners
+		*out = make([]UnavailableMiner, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceRef != nil {
+		in, out := &in.ServiceRef, &out.ServiceRef
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
 	if in.FailureReason != nil {
 		in, out := &in.FailureReason, &out.FailureReason
 		*out = new(string)
@@ -312,7 +427,7 @@ func (in *MinerSetStatus) DeepCopyInto(out *MinerSetStatus) {
 	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -332,9 +447,81 @@ func (in *MinerSetStatus) DeepCopy() *MinerSetStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MinerSpec) DeepCopyInto(out *MinerSpec) {
 	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Sidecars != nil {
+		in, out := &in.Sidecars, &out.Sidecars
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(v1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(v1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RuntimeClassName != nil {
+		in, out := &in.RuntimeClassName, &out.RuntimeClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.DNSConfig != nil {
+		in, out := &in.DNSConfig, &out.DNSConfig
+		*out = new(v1.PodDNSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostAliases != nil {
+		in, out := &in.HostAliases, &out.HostAliases
+		*out = make([]v1.HostAlias, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ManagePod != nil {
+		in, out := &in.ManagePod, &out.ManagePod
+		*out = new(bool)
+		**out = **in
+	}
 	if in.PodDeletionTimeout != nil {
 		in, out := &in.PodDeletionTimeout, &out.PodDeletionTimeout
-		*out = new(v1.Duration)
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.DrainTimeoutSeconds != nil {
+		in, out := &in.DrainTimeoutSeconds, &out.DrainTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureLogTailLines != nil {
+		in, out := &in.FailureLogTailLines, &out.FailureLogTailLines
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureLogLimitBytes != nil {
+		in, out := &in.FailureLogLimitBytes, &out.FailureLogLimitBytes
+		*out = new(int32)
 		**out = **in
 	}
 }
@@ -354,16 +541,20 @@ func (in *MinerStatus) DeepCopyInto(out *MinerStatus) {
 	*out = *in
 	if in.PodRef != nil {
 		in, out := &in.PodRef, &out.PodRef
-		*out = new(corev1.ObjectReference)
+		*out = new(v1.ObjectReference)
 		**out = **in
 	}
 	if in.LastUpdated != nil {
 		in, out := &in.LastUpdated, &out.LastUpdated
 		*out = (*in).DeepCopy()
 	}
+	if in.FirstRunningTime != nil {
+		in, out := &in.FirstRunningTime, &out.FirstRunningTime
+		*out = (*in).DeepCopy()
+	}
 	if in.FailureReason != nil {
 		in, out := &in.FailureReason, &out.FailureReason
-		*out = new(string)
+		*out = new(MinerFailureReason)
 		**out = **in
 	}
 	if in.FailureMessage != nil {
@@ -376,9 +567,14 @@ func (in *MinerStatus) DeepCopyInto(out *MinerStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ContainerStatuses != nil {
+		in, out := &in.ContainerStatuses, &out.ContainerStatuses
+		*out = make([]MinerContainerStatus, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -440,3 +636,18 @@ func (in *ObjectMeta) DeepCopy() *ObjectMeta {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnavailableMiner) DeepCopyInto(out *UnavailableMiner) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnavailableMiner.
+func (in *UnavailableMiner) DeepCopy() *UnavailableMiner {
+	if in == nil {
+		return nil
+	}
+	out := new(UnavailableMiner)
+	in.DeepCopyInto(out)
+	return out
+}
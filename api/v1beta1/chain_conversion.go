@@ -0,0 +1,107 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/ashwinyue/minerx/api/v1alpha1"
+)
+
+var _ conversion.Convertible = &Chain{}
+
+// ConvertTo converts this Chain to the Hub version (v1alpha1).
+func (src *Chain) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.Chain)
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec.DisplayName = src.Spec.DisplayName
+	dst.Spec.MinerType = src.Spec.MinerType
+	dst.Spec.MinerLabels = src.Spec.MinerLabels
+	dst.Spec.Image = src.Spec.Image
+	dst.Spec.MinMineIntervalSeconds = src.Spec.MinMineIntervalSeconds
+	dst.Spec.BootstrapAccount = src.Spec.BootstrapAccount
+	dst.Spec.ImagePullSecrets = src.Spec.ImagePullSecrets
+	dst.Spec.ExtraConfig = src.Spec.ExtraConfig
+	dst.Spec.WorkerReplicas = src.Spec.WorkerReplicas
+	dst.Spec.WorkerTemplate = nil
+	if src.Spec.WorkerTemplate != nil {
+		dst.Spec.WorkerTemplate = &v1alpha1.MinerTemplateSpec{}
+		convertMinerTemplateSpecTo(src.Spec.WorkerTemplate, dst.Spec.WorkerTemplate)
+	}
+	dst.Spec.ConfigMapName = src.Spec.ConfigMapName
+	dst.Spec.Paused = src.Spec.Paused
+
+	dst.Status.ConfigMapRef = convertLocalObjectReferenceTo(src.Status.ConfigMapRef)
+	dst.Status.MinerRef = convertLocalObjectReferenceTo(src.Status.MinerRef)
+	dst.Status.MinerSetRef = convertLocalObjectReferenceTo(src.Status.MinerSetRef)
+	dst.Status.FailureReason = src.Status.FailureReason
+	dst.Status.FailureMessage = src.Status.FailureMessage
+	dst.Status.MinerCount = src.Status.MinerCount
+	dst.Status.ReadyMinerCount = src.Status.ReadyMinerCount
+	dst.Status.ObservedGeneration = src.Status.ObservedGeneration
+	dst.Status.Conditions = src.Status.Conditions
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha1) to this version.
+func (dst *Chain) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.Chain)
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec.DisplayName = src.Spec.DisplayName
+	dst.Spec.MinerType = src.Spec.MinerType
+	dst.Spec.MinerLabels = src.Spec.MinerLabels
+	dst.Spec.Image = src.Spec.Image
+	dst.Spec.MinMineIntervalSeconds = src.Spec.MinMineIntervalSeconds
+	dst.Spec.BootstrapAccount = src.Spec.BootstrapAccount
+	dst.Spec.ImagePullSecrets = src.Spec.ImagePullSecrets
+	dst.Spec.ExtraConfig = src.Spec.ExtraConfig
+	dst.Spec.WorkerReplicas = src.Spec.WorkerReplicas
+	dst.Spec.WorkerTemplate = nil
+	if src.Spec.WorkerTemplate != nil {
+		dst.Spec.WorkerTemplate = &MinerTemplateSpec{}
+		convertMinerTemplateSpecFrom(src.Spec.WorkerTemplate, dst.Spec.WorkerTemplate)
+	}
+	dst.Spec.ConfigMapName = src.Spec.ConfigMapName
+	dst.Spec.Paused = src.Spec.Paused
+
+	dst.Status.ConfigMapRef = convertLocalObjectReferenceFrom(src.Status.ConfigMapRef)
+	dst.Status.MinerRef = convertLocalObjectReferenceFrom(src.Status.MinerRef)
+	dst.Status.MinerSetRef = convertLocalObjectReferenceFrom(src.Status.MinerSetRef)
+	dst.Status.FailureReason = src.Status.FailureReason
+	dst.Status.FailureMessage = src.Status.FailureMessage
+	dst.Status.MinerCount = src.Status.MinerCount
+	dst.Status.ReadyMinerCount = src.Status.ReadyMinerCount
+	dst.Status.ObservedGeneration = src.Status.ObservedGeneration
+	dst.Status.Conditions = src.Status.Conditions
+	return nil
+}
+
+func convertLocalObjectReferenceTo(src *LocalObjectReference) *v1alpha1.LocalObjectReference {
+	if src == nil {
+		return nil
+	}
+	return &v1alpha1.LocalObjectReference{Name: src.Name}
+}
+
+func convertLocalObjectReferenceFrom(src *v1alpha1.LocalObjectReference) *LocalObjectReference {
+	if src == nil {
+		return nil
+	}
+	return &LocalObjectReference{Name: src.Name}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/ashwinyue/minerx/api/v1alpha1"
+)
+
+func TestChainConversionRoundTrip(t *testing.T) {
+	g := NewWithT(t)
+
+	hub := &v1alpha1.Chain{
+		ObjectMeta: metav1.ObjectMeta{Name: "chain", Namespace: "default", Labels: map[string]string{"app": "chain"}},
+		Spec: v1alpha1.ChainSpec{
+			DisplayName:            "Chain",
+			MinerType:              "small",
+			MinerLabels:            map[string]string{"team": "a"},
+			Image:                  "chain:latest",
+			MinMineIntervalSeconds: 10,
+			BootstrapAccount:       ptr.To("account"),
+			ImagePullSecrets:       []corev1.LocalObjectReference{{Name: "secret"}},
+			ExtraConfig:            map[string]string{"key": "value"},
+			WorkerReplicas:         ptr.To[int32](3),
+			WorkerTemplate: &v1alpha1.MinerTemplateSpec{
+				ObjectMeta: v1alpha1.ObjectMeta{Labels: map[string]string{"role": "worker"}},
+				Spec:       v1alpha1.MinerSpec{ChainName: "chain", MinerType: v1alpha1.MinerTypeMedium},
+			},
+			ConfigMapName: ptr.To("chain-config"),
+			Paused:        true,
+		},
+		Status: v1alpha1.ChainStatus{
+			ConfigMapRef:       &v1alpha1.LocalObjectReference{Name: "chain-config"},
+			MinerRef:           &v1alpha1.LocalObjectReference{Name: "chain-genesis"},
+			MinerSetRef:        &v1alpha1.LocalObjectReference{Name: "chain-workers"},
+			FailureReason:      ptr.To("InvalidConfiguration"),
+			FailureMessage:     ptr.To("ConfigMap rejected"),
+			MinerCount:         4,
+			ReadyMinerCount:    2,
+			ObservedGeneration: 5,
+			Conditions: []metav1.Condition{{
+				Type:               "Ready",
+				Status:             metav1.ConditionTrue,
+				LastTransitionTime: metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+			}},
+		},
+	}
+
+	spoke := &Chain{}
+	g.Expect(spoke.ConvertFrom(hub)).To(Succeed())
+	g.Expect(spoke.Spec.WorkerTemplate.Spec.MinerType).To(Equal(MinerTypeMedium))
+
+	restored := &v1alpha1.Chain{}
+	g.Expect(spoke.ConvertTo(restored)).To(Succeed())
+	g.Expect(restored).To(Equal(hub))
+}
+
+func TestChainConversionEmpty(t *testing.T) {
+	g := NewWithT(t)
+
+	spoke := &Chain{}
+	hub := &v1alpha1.Chain{}
+	g.Expect(spoke.ConvertTo(hub)).To(Succeed())
+	g.Expect(hub).To(Equal(&v1alpha1.Chain{}))
+
+	restored := &Chain{}
+	g.Expect(restored.ConvertFrom(hub)).To(Succeed())
+	g.Expect(restored).To(Equal(spoke))
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LocalObjectReference contains enough information to let you locate the
+// referenced object inside the same namespace.
+type LocalObjectReference struct {
+	// Name of the referent.
+	// More info: https://kubernetes.io/docs/concepts/overview/working-with-objects/names/#names
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// ChainSpec defines the desired state of Chain
+type ChainSpec struct {
+	// DisplayName is the display name of the chain.
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// MinerType is the type of the genesis miner.
+	// +kubebuilder:validation:Enum=small;medium;large
+	// +optional
+	MinerType string `json:"minerType,omitempty"`
+
+	// MinerLabels are set on the genesis miner, and on its pod when the pod is created.
+	// Changes are applied to an existing genesis miner but not to its running pod.
+	// Labels managed by the controller, such as the chain name label, cannot be overridden.
+	// +optional
+	MinerLabels map[string]string `json:"minerLabels,omitempty"`
+
+	// Image is the blockchain node image.
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+
+	// MinMineIntervalSeconds is the minimum interval in seconds between mining operations.
+	// +optional
+	MinMineIntervalSeconds int32 `json:"minMineIntervalSeconds,omitempty"`
+
+	// BootstrapAccount is the bootstrap account (will be auto-generated).
+	// +optional
+	BootstrapAccount *string `json:"bootstrapAccount,omitempty"`
+
+	// ImagePullSecrets is a list of references to secrets in the same namespace used
+	// to pull the images of all miners belonging to the chain.
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// ExtraConfig is additional configuration merged into the chain ConfigMap.
+	// Keys managed by the controller, such as chainName and image, cannot be overridden.
+	// +optional
+	ExtraConfig map[string]string `json:"extraConfig,omitempty"`
+
+	// WorkerReplicas is the number of worker miners of the chain. When set, the controller
+	// manages a MinerSet for the workers in addition to the genesis miner.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	WorkerReplicas *int32 `json:"workerReplicas,omitempty"`
+
+	// WorkerTemplate describes the worker miners. Its ChainName is always set to the chain.
+	// Defaults to a miner of the chain's MinerType.
+	// +optional
+	WorkerTemplate *MinerTemplateSpec `json:"workerTemplate,omitempty"`
+
+	// ConfigMapName is the name of an existing ConfigMap in the chain namespace that
+	// holds the chain configuration. When set, the controller consumes this ConfigMap
+	// instead of creating its own, and ExtraConfig is ignored.
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	ConfigMapName *string `json:"configMapName,omitempty"`
+
+	// Paused freezes the chain, for example during maintenance. While paused, the controller
+	// does not create or update the chain resources but keeps the status current.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// ChainStatus defines the observed state of Chain
+type ChainStatus struct {
+	// ConfigMapRef points to the config map that contains the chain configuration.
+	// +optional
+	ConfigMapRef *LocalObjectReference `json:"configMapRef,omitempty"`
+
+	// MinerRef points to the genesis miner for this chain.
+	// +optional
+	MinerRef *LocalObjectReference `json:"minerRef,omitempty"`
+
+	// MinerSetRef points to the MinerSet of the chain's worker miners.
+	// +optional
+	MinerSetRef *LocalObjectReference `json:"minerSetRef,omitempty"`
+
+	// FailureReason will be set in the event that there is a terminal problem
+	// reconciling the chain and categorizes that problem.
+	// +optional
+	FailureReason *string `json:"failureReason,omitempty"`
+
+	// FailureMessage will be set in the event that there is a terminal problem
+	// reconciling the chain and contains a human readable description of it.
+	// +optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+
+	// MinerCount is the number of miners belonging to the chain, the genesis miner and workers included.
+	// +optional
+	MinerCount int32 `json:"minerCount,omitempty"`
+
+	// ReadyMinerCount is the number of miners belonging to the chain that are ready.
+	// +optional
+	ReadyMinerCount int32 `json:"readyMinerCount,omitempty"`
+
+	// ObservedGeneration is the latest generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the chain's current state.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Miners",type="integer",JSONPath=".status.minerCount"
+// +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyMinerCount"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// Chain is the Schema for the chains API
+type Chain struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ChainSpec   `json:"spec,omitempty"`
+	Status ChainStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ChainList contains a list of Chain
+type ChainList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Chain `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Chain{}, &ChainList{})
+}
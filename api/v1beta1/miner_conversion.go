@@ -0,0 +1,137 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/ashwinyue/minerx/api/v1alpha1"
+)
+
+var _ conversion.Convertible = &Miner{}
+
+// ConvertTo converts this Miner to the Hub version (v1alpha1).
+func (src *Miner) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.Miner)
+	dst.ObjectMeta = src.ObjectMeta
+	convertMinerSpecTo(&src.Spec, &dst.Spec)
+
+	dst.Status.PodRef = src.Status.PodRef
+	dst.Status.LastUpdated = src.Status.LastUpdated
+	dst.Status.FirstRunningTime = src.Status.FirstRunningTime
+	dst.Status.FailureReason = nil
+	if src.Status.FailureReason != nil {
+		reason := v1alpha1.MinerFailureReason(*src.Status.FailureReason)
+		dst.Status.FailureReason = &reason
+	}
+	dst.Status.FailureMessage = src.Status.FailureMessage
+	dst.Status.PodCreationFailures = src.Status.PodCreationFailures
+	dst.Status.Addresses = src.Status.Addresses
+	dst.Status.Zone = src.Status.Zone
+	dst.Status.Region = src.Status.Region
+	dst.Status.ContainerStatuses = nil
+	for _, cs := range src.Status.ContainerStatuses {
+		dst.Status.ContainerStatuses = append(dst.Status.ContainerStatuses, v1alpha1.MinerContainerStatus(cs))
+	}
+	dst.Status.Phase = v1alpha1.MinerPhase(src.Status.Phase)
+	dst.Status.Ready = src.Status.Ready
+	dst.Status.ObservedGeneration = src.Status.ObservedGeneration
+	dst.Status.Conditions = src.Status.Conditions
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha1) to this version.
+func (dst *Miner) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.Miner)
+	dst.ObjectMeta = src.ObjectMeta
+	convertMinerSpecFrom(&src.Spec, &dst.Spec)
+
+	dst.Status.PodRef = src.Status.PodRef
+	dst.Status.LastUpdated = src.Status.LastUpdated
+	dst.Status.FirstRunningTime = src.Status.FirstRunningTime
+	dst.Status.FailureReason = nil
+	if src.Status.FailureReason != nil {
+		reason := MinerFailureReason(*src.Status.FailureReason)
+		dst.Status.FailureReason = &reason
+	}
+	dst.Status.FailureMessage = src.Status.FailureMessage
+	dst.Status.PodCreationFailures = src.Status.PodCreationFailures
+	dst.Status.Addresses = src.Status.Addresses
+	dst.Status.Zone = src.Status.Zone
+	dst.Status.Region = src.Status.Region
+	dst.Status.ContainerStatuses = nil
+	for _, cs := range src.Status.ContainerStatuses {
+		dst.Status.ContainerStatuses = append(dst.Status.ContainerStatuses, MinerContainerStatus(cs))
+	}
+	dst.Status.Phase = MinerPhase(src.Status.Phase)
+	dst.Status.Ready = src.Status.Ready
+	dst.Status.ObservedGeneration = src.Status.ObservedGeneration
+	dst.Status.Conditions = src.Status.Conditions
+	return nil
+}
+
+func convertMinerSpecTo(src *MinerSpec, dst *v1alpha1.MinerSpec) {
+	dst.DisplayName = src.DisplayName
+	dst.MinerType = v1alpha1.MinerType(src.MinerType)
+	dst.Resources = src.Resources
+	dst.ChainName = src.ChainName
+	dst.ContainerName = src.ContainerName
+	dst.RestartPolicy = src.RestartPolicy
+	dst.ActiveDeadlineSeconds = src.ActiveDeadlineSeconds
+	dst.ImagePullSecrets = src.ImagePullSecrets
+	dst.InitContainers = src.InitContainers
+	dst.ChainConfigInitContainer = src.ChainConfigInitContainer
+	dst.Sidecars = src.Sidecars
+	dst.SecurityContext = src.SecurityContext
+	dst.PodSecurityContext = src.PodSecurityContext
+	dst.PriorityClassName = src.PriorityClassName
+	dst.RuntimeClassName = src.RuntimeClassName
+	dst.DNSPolicy = src.DNSPolicy
+	dst.DNSConfig = src.DNSConfig
+	dst.HostAliases = src.HostAliases
+	dst.ManagePod = src.ManagePod
+	dst.PodDeletionTimeout = src.PodDeletionTimeout
+	dst.DrainTimeoutSeconds = src.DrainTimeoutSeconds
+	dst.FailureLogTailLines = src.FailureLogTailLines
+	dst.FailureLogLimitBytes = src.FailureLogLimitBytes
+}
+
+func convertMinerSpecFrom(src *v1alpha1.MinerSpec, dst *MinerSpec) {
+	dst.DisplayName = src.DisplayName
+	dst.MinerType = MinerType(src.MinerType)
+	dst.Resources = src.Resources
+	dst.ChainName = src.ChainName
+	dst.ContainerName = src.ContainerName
+	dst.RestartPolicy = src.RestartPolicy
+	dst.ActiveDeadlineSeconds = src.ActiveDeadlineSeconds
+	dst.ImagePullSecrets = src.ImagePullSecrets
+	dst.InitContainers = src.InitContainers
+	dst.ChainConfigInitContainer = src.ChainConfigInitContainer
+	dst.Sidecars = src.Sidecars
+	dst.SecurityContext = src.SecurityContext
+	dst.PodSecurityContext = src.PodSecurityContext
+	dst.PriorityClassName = src.PriorityClassName
+	dst.RuntimeClassName = src.RuntimeClassName
+	dst.DNSPolicy = src.DNSPolicy
+	dst.DNSConfig = src.DNSConfig
+	dst.HostAliases = src.HostAliases
+	dst.ManagePod = src.ManagePod
+	dst.PodDeletionTimeout = src.PodDeletionTimeout
+	dst.DrainTimeoutSeconds = src.DrainTimeoutSeconds
+	dst.FailureLogTailLines = src.FailureLogTailLines
+	dst.FailureLogLimitBytes = src.FailureLogLimitBytes
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/ashwinyue/minerx/api/v1alpha1"
+)
+
+func TestMinerConversionRoundTrip(t *testing.T) {
+	g := NewWithT(t)
+
+	reason := v1alpha1.MinerFailureReasonCrashLoop
+	lastUpdated := metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	hub := &v1alpha1.Miner{
+		ObjectMeta: metav1.ObjectMeta{Name: "miner", Namespace: "default"},
+		Spec: v1alpha1.MinerSpec{
+			DisplayName:              "Miner",
+			MinerType:                v1alpha1.MinerTypeLarge,
+			Resources:                corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}},
+			ChainName:                "chain",
+			ContainerName:            "worker",
+			RestartPolicy:            corev1.RestartPolicyOnFailure,
+			ActiveDeadlineSeconds:    ptr.To[int64](3600),
+			ImagePullSecrets:         []corev1.LocalObjectReference{{Name: "secret"}},
+			InitContainers:           []corev1.Container{{Name: "init", Image: "busybox"}},
+			ChainConfigInitContainer: true,
+			Sidecars:                 []corev1.Container{{Name: "exporter", Image: "exporter:latest"}},
+			SecurityContext:          &corev1.SecurityContext{RunAsNonRoot: ptr.To(true)},
+			PodSecurityContext:       &corev1.PodSecurityContext{RunAsUser: ptr.To[int64](1000)},
+			PriorityClassName:        "miner-critical",
+			RuntimeClassName:         ptr.To("kata"),
+			DNSPolicy:                corev1.DNSNone,
+			DNSConfig:                &corev1.PodDNSConfig{Nameservers: []string{"10.0.0.53"}},
+			HostAliases:              []corev1.HostAlias{{IP: "10.0.0.2", Hostnames: []string{"seed"}}},
+			ManagePod:                ptr.To(false),
+			PodDeletionTimeout:       &metav1.Duration{Duration: time.Minute},
+			DrainTimeoutSeconds:      ptr.To[int32](30),
+			FailureLogTailLines:      ptr.To[int32](20),
+			FailureLogLimitBytes:     ptr.To[int32](2048),
+		},
+		Status: v1alpha1.MinerStatus{
+			PodRef:              &corev1.ObjectReference{Kind: "Pod", Name: "miner"},
+			LastUpdated:         &lastUpdated,
+			FirstRunningTime:    &lastUpdated,
+			FailureReason:       &reason,
+			FailureMessage:      ptr.To("Container miner: back-off"),
+			PodCreationFailures: 2,
+			Addresses:           []string{"10.0.0.1"},
+			ContainerStatuses: []v1alpha1.MinerContainerStatus{
+				{Name: "miner", Ready: true, RestartCount: 4},
+				{Name: "exporter", Ready: false},
+			},
+			Zone:               "eu-west-1a",
+			Region:             "eu-west-1",
+			Phase:              v1alpha1.MinerPhaseFailed,
+			Ready:              true,
+			ObservedGeneration: 3,
+			Conditions: []metav1.Condition{{
+				Type:               "PodHealthy",
+				Status:             metav1.ConditionFalse,
+				Reason:             "Failed",
+				LastTransitionTime: lastUpdated,
+			}},
+		},
+	}
+
+	spoke := &Miner{}
+	g.Expect(spoke.ConvertFrom(hub)).To(Succeed())
+	g.Expect(spoke.Spec.MinerType).To(Equal(MinerTypeLarge))
+	g.Expect(spoke.Status.Phase).To(Equal(MinerPhaseFailed))
+	g.Expect(*spoke.Status.FailureReason).To(Equal(MinerFailureReasonCrashLoop))
+
+	restored := &v1alpha1.Miner{}
+	g.Expect(spoke.ConvertTo(restored)).To(Succeed())
+	g.Expect(restored).To(Equal(hub))
+}
+
+func TestMinerConversionEmpty(t *testing.T) {
+	g := NewWithT(t)
+
+	spoke := &Miner{}
+	hub := &v1alpha1.Miner{}
+	g.Expect(spoke.ConvertTo(hub)).To(Succeed())
+	g.Expect(hub).To(Equal(&v1alpha1.Miner{}))
+
+	restored := &Miner{}
+	g.Expect(restored.ConvertFrom(hub)).To(Succeed())
+	g.Expect(restored).To(Equal(spoke))
+}
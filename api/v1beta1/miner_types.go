@@ -0,0 +1,336 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:MinLength=1
+// +kubebuilder:validation:MaxLength=63
+type MinerType string
+
+const (
+	MinerTypeSmall  MinerType = "small"
+	MinerTypeMedium MinerType = "medium"
+	MinerTypeLarge  MinerType = "large"
+)
+
+// MinerPhase is the phase of a miner at the current time.
+type MinerPhase string
+
+const (
+	// MinerPhasePending means the miner has been accepted by the system, but one or more of the
+	// required resources have not been created.
+	MinerPhasePending MinerPhase = "Pending"
+
+	// MinerPhaseProvisioning means the system is provisioning infrastructure for the miner.
+	MinerPhaseProvisioning MinerPhase = "Provisioning"
+
+	// MinerPhaseRunning means the miner has become a running miner and is ready to mine.
+	MinerPhaseRunning MinerPhase = "Running"
+
+	// MinerPhaseDraining means the miner has been requested to be deleted and is waiting
+	// for its pod to drain before the pod is deleted.
+	MinerPhaseDraining MinerPhase = "Draining"
+
+	// MinerPhaseDeleting means the miner has been requested to be deleted and a deletion
+	// timestamp is set.
+	MinerPhaseDeleting MinerPhase = "Deleting"
+
+	// MinerPhaseSucceeded means the pod of the miner ran to completion and will not be
+	// restarted, such as with a RestartPolicy of Never.
+	MinerPhaseSucceeded MinerPhase = "Succeeded"
+
+	// MinerPhaseFailed means the system may require user intervention.
+	MinerPhaseFailed MinerPhase = "Failed"
+)
+
+// MinerFailureReason is the category of a terminal problem reconciling a miner.
+// The set of categories may grow, so clients should handle values they do not know.
+type MinerFailureReason string
+
+const (
+	// MinerFailureReasonImagePullError means the miner image could not be pulled.
+	MinerFailureReasonImagePullError MinerFailureReason = "ImagePullError"
+
+	// MinerFailureReasonCrashLoop means a miner container keeps crashing.
+	MinerFailureReasonCrashLoop MinerFailureReason = "CrashLoop"
+
+	// MinerFailureReasonOOMKilled means a miner container was killed for running out of memory.
+	MinerFailureReasonOOMKilled MinerFailureReason = "OOMKilled"
+
+	// MinerFailureReasonUnschedulable means the miner pod could not be scheduled to a node.
+	MinerFailureReasonUnschedulable MinerFailureReason = "Unschedulable"
+
+	// MinerFailureReasonUnknown means the failure could not be categorized.
+	MinerFailureReasonUnknown MinerFailureReason = "Unknown"
+)
+
+// MinerSpec defines the desired state of Miner
+type MinerSpec struct {
+	// DisplayName is the display name of the miner.
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// MinerType is the type of the miner.
+	// +kubebuilder:validation:Enum=small;medium;large
+	// +optional
+	MinerType MinerType `json:"minerType,omitempty"`
+
+	// Resources are the compute resources of the miner container. When empty, they are
+	// defaulted on admission from the resource profile of the miner type.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// ChainName is the name of the chain this miner belongs to.
+	// +kubebuilder:validation:MinLength=1
+	ChainName string `json:"chainName"`
+
+	// ContainerName is the name of the miner container in the pod. It must be a DNS
+	// label and must not be used by any sidecar.
+	// Defaults to "miner".
+	// +kubebuilder:default=miner
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +optional
+	ContainerName string `json:"containerName,omitempty"`
+
+	// RestartPolicy for the miner.
+	// +kubebuilder:validation:Enum=Always;OnFailure;Never
+	// +optional
+	RestartPolicy corev1.RestartPolicy `json:"restartPolicy,omitempty"`
+
+	// ActiveDeadlineSeconds is the duration in seconds the miner pod may be active before
+	// it is terminated. Combined with a RestartPolicy of Never, this bounds batch-style miners.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// ImagePullSecrets is a list of references to secrets in the same namespace used
+	// to pull the miner image. Secrets of the miner's Chain are added to this list.
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// InitContainers run in order before the miner container starts, for example to
+	// download and verify chain data.
+	// +optional
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+
+	// ChainConfigInitContainer adds an init container, ahead of InitContainers, that
+	// mounts the ConfigMap of the miner's Chain at /etc/chain. The pod is only created
+	// once the chain has created its ConfigMap. Defaults to false.
+	// +optional
+	ChainConfigInitContainer bool `json:"chainConfigInitContainer,omitempty"`
+
+	// Sidecars are additional containers, such as metrics exporters, that run in the
+	// miner pod after the main miner container. Their names must be unique and must
+	// not be ContainerName.
+	// +optional
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+
+	// SecurityContext is the security context applied to the miner container.
+	// Defaults to a restricted security context when unset.
+	// +optional
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
+	// PodSecurityContext is the security context applied to the miner pod.
+	// Defaults to a restricted security context when unset.
+	// +optional
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+
+	// PriorityClassName is the name of the PriorityClass of the miner pod, which sets its
+	// scheduling priority and whether it may preempt lower priority pods.
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// RuntimeClassName is the name of the RuntimeClass used to run the miner pod. The
+	// resource overhead defined by the RuntimeClass is accounted for on top of the container
+	// requests when scheduling the pod and enforcing resource quotas.
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	// +optional
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
+
+	// DNSPolicy is the DNS policy of the miner pod.
+	// Defaults to ClusterFirst.
+	// +kubebuilder:validation:Enum=ClusterFirstWithHostNet;ClusterFirst;Default;None
+	// +optional
+	DNSPolicy corev1.DNSPolicy `json:"dnsPolicy,omitempty"`
+
+	// DNSConfig are DNS parameters of the miner pod, merged with the ones generated from
+	// DNSPolicy. They are required when DNSPolicy is None.
+	// +optional
+	DNSConfig *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+
+	// HostAliases are entries added to the hosts file of the miner pod, for example to
+	// reach peers by name without DNS.
+	// +optional
+	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
+
+	// ManagePod defines whether the controller creates and deletes the miner pod.
+	// When false, the pod is expected to be managed externally and the controller only
+	// tracks the status of a pod named after the miner or labeled with its name.
+	// Defaults to true.
+	// +kubebuilder:default=true
+	// +optional
+	ManagePod *bool `json:"managePod,omitempty"`
+
+	// PodDeletionTimeout defines how long the controller will attempt to delete the pod.
+	// A duration of 0 will retry deletion indefinitely.
+	// Defaults to 10 seconds.
+	// +optional
+	PodDeletionTimeout *metav1.Duration `json:"podDeletionTimeout,omitempty"`
+
+	// DrainTimeoutSeconds makes the controller drain the miner before deleting its pod.
+	// The pod is annotated with miner.onex.io/drain-requested and the miner enters the
+	// Draining phase until the pod is annotated with miner.onex.io/drained, or until this
+	// many seconds have passed since the miner deletion. No drain happens when unset.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	DrainTimeoutSeconds *int32 `json:"drainTimeoutSeconds,omitempty"`
+
+	// FailureLogTailLines makes the controller capture this many lines from the end of the
+	// miner container logs into the failure message when the pod of the miner fails.
+	// No logs are captured when unset.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1000
+	// +optional
+	FailureLogTailLines *int32 `json:"failureLogTailLines,omitempty"`
+
+	// FailureLogLimitBytes caps the size of the logs captured into the failure message,
+	// the oldest lines are dropped first. Defaults to 1024 bytes.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=16384
+	// +optional
+	FailureLogLimitBytes *int32 `json:"failureLogLimitBytes,omitempty"`
+}
+
+// MinerContainerStatus is the readiness of a single container of the miner pod.
+type MinerContainerStatus struct {
+	// Name is the name of the container.
+	Name string `json:"name"`
+
+	// Ready is true when the container is passing its readiness probe.
+	Ready bool `json:"ready"`
+
+	// RestartCount is the number of times the container has been restarted.
+	// +optional
+	RestartCount int32 `json:"restartCount,omitempty"`
+}
+
+// MinerStatus defines the observed state of Miner
+type MinerStatus struct {
+	// PodRef will point to the corresponding pod if it exists.
+	// +optional
+	PodRef *corev1.ObjectReference `json:"podRef,omitempty"`
+
+	// LastUpdated identifies when this status was last observed.
+	// +optional
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+
+	// FirstRunningTime is when the miner first reached the Running phase. It is kept
+	// when the miner fails or its pod is recreated afterwards.
+	// +optional
+	FirstRunningTime *metav1.Time `json:"firstRunningTime,omitempty"`
+
+	// FailureReason will be set in the event that there is a terminal problem
+	// reconciling the miner and categorizes that problem.
+	// +optional
+	FailureReason *MinerFailureReason `json:"failureReason,omitempty"`
+
+	// FailureMessage will be set in the event that there is a terminal problem
+	// reconciling the miner and contains a human readable description of it.
+	// +optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+
+	// PodCreationFailures is the number of consecutive failed attempts to create the
+	// miner pod. It is reset once the pod has been created successfully.
+	// +optional
+	PodCreationFailures int32 `json:"podCreationFailures,omitempty"`
+
+	// Addresses is a list of addresses assigned to the miner.
+	// +optional
+	Addresses []string `json:"addresses,omitempty"`
+
+	// ContainerStatuses lists the readiness of each container of the miner pod.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	ContainerStatuses []MinerContainerStatus `json:"containerStatuses,omitempty"`
+
+	// Zone is the topology zone of the node the miner pod is scheduled on,
+	// read from the topology.kubernetes.io/zone node label.
+	// +optional
+	Zone string `json:"zone,omitempty"`
+
+	// Region is the topology region of the node the miner pod is scheduled on,
+	// read from the topology.kubernetes.io/region node label.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Phase represents the current phase of miner actuation.
+	// One of: Failed, Provisioning, Pending, Running, Draining, Deleting
+	// +optional
+	Phase MinerPhase `json:"phase,omitempty"`
+
+	// Ready is true when the miner is Running and its pod is healthy.
+	// +optional
+	Ready bool `json:"ready,omitempty"`
+
+	// ObservedGeneration is the latest generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the miner's current state.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Chain",type="string",JSONPath=".spec.chainName"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Ready",type="boolean",JSONPath=".status.ready"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// Miner is the Schema for the miners API
+type Miner struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MinerSpec   `json:"spec,omitempty"`
+	Status MinerStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MinerList contains a list of Miner
+type MinerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Miner `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Miner{}, &MinerList{})
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/ashwinyue/minerx/api/v1alpha1"
+)
+
+var _ conversion.Convertible = &MinerSet{}
+
+// ConvertTo converts this MinerSet to the Hub version (v1alpha1).
+func (src *MinerSet) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.MinerSet)
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec.Replicas = src.Spec.Replicas
+	dst.Spec.Selector = src.Spec.Selector
+	convertMinerTemplateSpecTo(&src.Spec.Template, &dst.Spec.Template)
+	dst.Spec.DisplayName = src.Spec.DisplayName
+	dst.Spec.DeletePolicy = v1alpha1.DeletePolicy(src.Spec.DeletePolicy)
+	dst.Spec.NamingStrategy = v1alpha1.MinerNamingStrategy(src.Spec.NamingStrategy)
+	dst.Spec.MinReadySeconds = src.Spec.MinReadySeconds
+	dst.Spec.ProgressDeadlineSeconds = src.Spec.ProgressDeadlineSeconds
+	dst.Spec.MaxProvisioning = src.Spec.MaxProvisioning
+	dst.Spec.WaitForChainReady = src.Spec.WaitForChainReady
+	dst.Spec.HeadlessService = src.Spec.HeadlessService
+	dst.Spec.AdoptOrphans = src.Spec.AdoptOrphans
+	dst.Spec.PodDisruptionBudget = nil
+	if pdb := src.Spec.PodDisruptionBudget; pdb != nil {
+		dst.Spec.PodDisruptionBudget = &v1alpha1.MinerSetPodDisruptionBudget{
+			MinAvailable:   pdb.MinAvailable,
+			MaxUnavailable: pdb.MaxUnavailable,
+		}
+	}
+
+	dst.Status.Replicas = src.Status.Replicas
+	dst.Status.FullyLabeledReplicas = src.Status.FullyLabeledReplicas
+	dst.Status.ReadyReplicas = src.Status.ReadyReplicas
+	dst.Status.AvailableReplicas = src.Status.AvailableReplicas
+	dst.Status.UnavailableMiners = nil
+	for _, miner := range src.Status.UnavailableMiners {
+		dst.Status.UnavailableMiners = append(dst.Status.UnavailableMiners, v1alpha1.UnavailableMiner{
+			Name:  miner.Name,
+			Phase: v1alpha1.MinerPhase(miner.Phase),
+		})
+	}
+	dst.Status.ServiceRef = convertLocalObjectReferenceTo(src.Status.ServiceRef)
+	dst.Status.ObservedTemplateHash = src.Status.ObservedTemplateHash
+	dst.Status.ObservedGeneration = src.Status.ObservedGeneration
+	dst.Status.FailureReason = src.Status.FailureReason
+	dst.Status.FailureMessage = src.Status.FailureMessage
+	dst.Status.Conditions = src.Status.Conditions
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha1) to this version.
+func (dst *MinerSet) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.MinerSet)
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec.Replicas = src.Spec.Replicas
+	dst.Spec.Selector = src.Spec.Selector
+	convertMinerTemplateSpecFrom(&src.Spec.Template, &dst.Spec.Template)
+	dst.Spec.DisplayName = src.Spec.DisplayName
+	dst.Spec.DeletePolicy = DeletePolicy(src.Spec.DeletePolicy)
+	dst.Spec.NamingStrategy = MinerNamingStrategy(src.Spec.NamingStrategy)
+	dst.Spec.MinReadySeconds = src.Spec.MinReadySeconds
+	dst.Spec.ProgressDeadlineSeconds = src.Spec.ProgressDeadlineSeconds
+	dst.Spec.MaxProvisioning = src.Spec.MaxProvisioning
+	dst.Spec.WaitForChainReady = src.Spec.WaitForChainReady
+	dst.Spec.HeadlessService = src.Spec.HeadlessService
+	dst.Spec.AdoptOrphans = src.Spec.AdoptOrphans
+	dst.Spec.PodDisruptionBudget = nil
+	if pdb := src.Spec.PodDisruptionBudget; pdb != nil {
+		dst.Spec.PodDisruptionBudget = &MinerSetPodDisruptionBudget{
+			MinAvailable:   pdb.MinAvailable,
+			MaxUnavailable: pdb.MaxUnavailable,
+		}
+	}
+
+	dst.Status.Replicas = src.Status.Replicas
+	dst.Status.FullyLabeledReplicas = src.Status.FullyLabeledReplicas
+	dst.Status.ReadyReplicas = src.Status.ReadyReplicas
+	dst.Status.AvailableReplicas = src.Status.AvailableReplicas
+	dst.Status.UnavailableMiners = nil
+	for _, miner := range src.Status.UnavailableMiners {
+		dst.Status.UnavailableMiners = append(dst.Status.UnavailableMiners, UnavailableMiner{
+			Name:  miner.Name,
+			Phase: MinerPhase(miner.Phase),
+		})
+	}
+	dst.Status.ServiceRef = convertLocalObjectReferenceFrom(src.Status.ServiceRef)
+	dst.Status.ObservedTemplateHash = src.Status.ObservedTemplateHash
+	dst.Status.ObservedGeneration = src.Status.ObservedGeneration
+	dst.Status.FailureReason = src.Status.FailureReason
+	dst.Status.FailureMessage = src.Status.FailureMessage
+	dst.Status.Conditions = src.Status.Conditions
+	return nil
+}
+
+func convertMinerTemplateSpecTo(src *MinerTemplateSpec, dst *v1alpha1.MinerTemplateSpec) {
+	dst.ObjectMeta.Labels = src.ObjectMeta.Labels
+	dst.ObjectMeta.Annotations = src.ObjectMeta.Annotations
+	convertMinerSpecTo(&src.Spec, &dst.Spec)
+}
+
+func convertMinerTemplateSpecFrom(src *v1alpha1.MinerTemplateSpec, dst *MinerTemplateSpec) {
+	dst.ObjectMeta.Labels = src.ObjectMeta.Labels
+	dst.ObjectMeta.Annotations = src.ObjectMeta.Annotations
+	convertMinerSpecFrom(&src.Spec, &dst.Spec)
+}
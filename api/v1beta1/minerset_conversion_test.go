@@ -0,0 +1,94 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	"github.com/ashwinyue/minerx/api/v1alpha1"
+)
+
+func TestMinerSetConversionRoundTrip(t *testing.T) {
+	g := NewWithT(t)
+
+	hub := &v1alpha1.MinerSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "minerset", Namespace: "default"},
+		Spec: v1alpha1.MinerSetSpec{
+			Replicas: ptr.To[int32](3),
+			Selector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "miner"}},
+			Template: v1alpha1.MinerTemplateSpec{
+				ObjectMeta: v1alpha1.ObjectMeta{
+					Labels:      map[string]string{"app": "miner"},
+					Annotations: map[string]string{"example.com/chain": "{{ .ChainName }}"},
+				},
+				Spec: v1alpha1.MinerSpec{ChainName: "chain", MinerType: v1alpha1.MinerTypeSmall},
+			},
+			DisplayName:             "MinerSet",
+			DeletePolicy:            v1alpha1.DeletePolicyOldest,
+			NamingStrategy:          v1alpha1.MinerNamingStrategyOrdinal,
+			MinReadySeconds:         30,
+			ProgressDeadlineSeconds: ptr.To[int32](600),
+			MaxProvisioning:         ptr.To[int32](2),
+			WaitForChainReady:       true,
+			HeadlessService:         true,
+			AdoptOrphans:            ptr.To(false),
+			PodDisruptionBudget: &v1alpha1.MinerSetPodDisruptionBudget{
+				MaxUnavailable: ptr.To(intstr.FromString("25%")),
+			},
+		},
+		Status: v1alpha1.MinerSetStatus{
+			Replicas:             3,
+			FullyLabeledReplicas: 3,
+			ReadyReplicas:        2,
+			AvailableReplicas:    2,
+			UnavailableMiners:    []v1alpha1.UnavailableMiner{{Name: "minerset-abcde", Phase: v1alpha1.MinerPhaseProvisioning}},
+			ServiceRef:           &v1alpha1.LocalObjectReference{Name: "minerset"},
+			ObservedTemplateHash: "5d8f7c9b",
+			ObservedGeneration:   7,
+			FailureReason:        ptr.To("ProgressDeadlineExceeded"),
+			FailureMessage:       ptr.To("MinerSet did not progress"),
+			Conditions:           []metav1.Condition{{Type: "MinersReady", Status: metav1.ConditionFalse, Reason: "Provisioning"}},
+		},
+	}
+
+	spoke := &MinerSet{}
+	g.Expect(spoke.ConvertFrom(hub)).To(Succeed())
+	g.Expect(spoke.Spec.DeletePolicy).To(Equal(DeletePolicyOldest))
+	g.Expect(spoke.Spec.Template.Spec.MinerType).To(Equal(MinerTypeSmall))
+
+	restored := &v1alpha1.MinerSet{}
+	g.Expect(spoke.ConvertTo(restored)).To(Succeed())
+	g.Expect(restored).To(Equal(hub))
+}
+
+func TestMinerSetConversionEmpty(t *testing.T) {
+	g := NewWithT(t)
+
+	spoke := &MinerSet{}
+	hub := &v1alpha1.MinerSet{}
+	g.Expect(spoke.ConvertTo(hub)).To(Succeed())
+	g.Expect(hub).To(Equal(&v1alpha1.MinerSet{}))
+
+	restored := &MinerSet{}
+	g.Expect(restored.ConvertFrom(hub)).To(Succeed())
+	g.Expect(restored).To(Equal(spoke))
+}
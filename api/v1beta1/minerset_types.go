@@ -0,0 +1,261 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// MinerTemplateSpec defines the miner template
+type MinerTemplateSpec struct {
+	// Standard object's metadata.
+	// +optional
+	ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Specification of the desired behavior of the miner.
+	// +optional
+	Spec MinerSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// ObjectMeta is metadata that will be autopopulated for the pod created.
+type ObjectMeta struct {
+	// Map of string keys and values that can be used to organize and categorize
+	// (scope and select) objects.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty" protobuf:"bytes,1,rep,name=labels"`
+
+	// Annotations is an unstructured key value map stored with an object.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty" protobuf:"bytes,2,rep,name=annotations"`
+}
+
+// DeletePolicy defines the delete policy for the pods when a MinerSet scales down.
+type DeletePolicy string
+
+const (
+	// DeletePolicyRandom chooses a random pod to delete.
+	DeletePolicyRandom DeletePolicy = "Random"
+
+	// DeletePolicyNewest deletes the newest pod.
+	DeletePolicyNewest DeletePolicy = "Newest"
+
+	// DeletePolicyOldest deletes the oldest pod.
+	DeletePolicyOldest DeletePolicy = "Oldest"
+
+	// DeletePolicyUnhealthyFirst deletes the least healthy pod, ranked by its phase,
+	// readiness and container restarts.
+	DeletePolicyUnhealthyFirst DeletePolicy = "UnhealthyFirst"
+)
+
+// MinerNamingStrategy defines how a MinerSet names the miners it creates.
+type MinerNamingStrategy string
+
+const (
+	// MinerNamingStrategyGenerateName names miners after the MinerSet with a random suffix.
+	MinerNamingStrategyGenerateName MinerNamingStrategy = "GenerateName"
+
+	// MinerNamingStrategyOrdinal names miners after the MinerSet with the lowest free
+	// ordinal, from <minerset>-0 to <minerset>-<replicas-1>.
+	MinerNamingStrategyOrdinal MinerNamingStrategy = "Ordinal"
+)
+
+// MinerSetSpec defines the desired state of MinerSet
+type MinerSetSpec struct {
+	// Replicas is the number of desired replicas. An unset value is defaulted on
+	// admission, to 1 unless configured otherwise, and zero
+	// scales the MinerSet down to no miners.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Selector is a label query over pods that should match the replica count.
+	// More info: https://kubernetes.io/docs/concepts/overview/working-with-objects/labels/
+	// +optional
+	Selector metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Template is the object that describes the miner that will be created
+	// if insufficient replicas are detected.
+	Template MinerTemplateSpec `json:"template,omitempty"`
+
+	// DisplayName is the display name of the MinerSet.
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// DeletePolicy defines the delete policy for the pods when a MinerSet scales down.
+	// Default to Random.
+	// +kubebuilder:validation:Enum=Random;Newest;Oldest;UnhealthyFirst
+	// +optional
+	DeletePolicy DeletePolicy `json:"deletePolicy,omitempty"`
+
+	// NamingStrategy defines how the MinerSet names the miners it creates. Ordinal gives
+	// miners stable names that are reused once a scale down frees them.
+	// Default to GenerateName.
+	// +kubebuilder:validation:Enum=GenerateName;Ordinal
+	// +optional
+	NamingStrategy MinerNamingStrategy `json:"namingStrategy,omitempty"`
+
+	// MinReadySeconds is the minimum number of seconds for which a newly created pod should
+	// be ready without any of its container crashing, for it to be considered available.
+	// Defaults to 0.
+	// +optional
+	MinReadySeconds int32 `json:"minReadySeconds,omitempty"`
+
+	// ProgressDeadlineSeconds is the maximum duration in seconds that a MinerSet may take
+	// to progress, before it is considered to be failed.
+	// Defaults to 600 on admission.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	// MaxProvisioning is the maximum number of miners that can be provisioning at the same
+	// time, counting the miners that have not reached the Running or Failed phase yet.
+	// Scale-ups create more miners as earlier ones start running. No limit applies when unset.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxProvisioning *int32 `json:"maxProvisioning,omitempty"`
+
+	// WaitForChainReady makes the MinerSet wait until the Chain referenced by the
+	// template's ChainName has created its ConfigMap before creating any miners.
+	// Defaults to false.
+	// +optional
+	WaitForChainReady bool `json:"waitForChainReady,omitempty"`
+
+	// HeadlessService makes the MinerSet create a headless Service, named after the
+	// MinerSet, that selects its miners so that they can discover their peers over DNS.
+	// Defaults to false.
+	// +optional
+	HeadlessService bool `json:"headlessService,omitempty"`
+
+	// AdoptOrphans defines whether the MinerSet adopts miners without a controller that
+	// match its selector. When false, such miners are left alone.
+	// Defaults to true.
+	// +kubebuilder:default=true
+	// +optional
+	AdoptOrphans *bool `json:"adoptOrphans,omitempty"`
+
+	// PodDisruptionBudget makes the MinerSet create a PodDisruptionBudget, named after
+	// the MinerSet, that protects its miners from voluntary disruptions such as node drains.
+	// +optional
+	PodDisruptionBudget *MinerSetPodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+}
+
+// MinerSetPodDisruptionBudget describes the PodDisruptionBudget of a MinerSet's miners.
+// At most one of MinAvailable and MaxUnavailable can be set.
+// +kubebuilder:validation:XValidation:rule="!(has(self.minAvailable) && has(self.maxUnavailable))",message="minAvailable and maxUnavailable are mutually exclusive"
+type MinerSetPodDisruptionBudget struct {
+	// MinAvailable is the number or percentage of miners that must remain available during
+	// an eviction. An absolute number is capped to the MinerSet replicas.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// MaxUnavailable is the number or percentage of miners that can be unavailable during
+	// an eviction.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// UnavailableMiner identifies a miner of a MinerSet that is not ready.
+type UnavailableMiner struct {
+	// Name of the miner.
+	Name string `json:"name"`
+
+	// Phase is the current phase of the miner.
+	// +optional
+	Phase MinerPhase `json:"phase,omitempty"`
+}
+
+// MinerSetStatus defines the observed state of MinerSet
+type MinerSetStatus struct {
+	// Replicas is the most recently observed number of replicas.
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// FullyLabeledReplicas is the number of pods that have all of the requested labels.
+	// +optional
+	FullyLabeledReplicas int32 `json:"fullyLabeledReplicas,omitempty"`
+
+	// ReadyReplicas is the number of ready pods.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// AvailableReplicas is the number of available pods.
+	// +optional
+	AvailableReplicas int32 `json:"availableReplicas,omitempty"`
+
+	// UnavailableMiners lists the miners that are not ready, sorted by name and capped
+	// to a small number of entries, to help finding problem miners.
+	// +optional
+	UnavailableMiners []UnavailableMiner `json:"unavailableMiners,omitempty"`
+
+	// ServiceRef points to the headless Service of the MinerSet's miners.
+	// +optional
+	ServiceRef *LocalObjectReference `json:"serviceRef,omitempty"`
+
+	// ObservedTemplateHash is the hash of the miner template the controller last acted on.
+	// Miners created from it carry the same hash in their template hash annotation.
+	// +optional
+	ObservedTemplateHash string `json:"observedTemplateHash,omitempty"`
+
+	// ObservedGeneration is the latest generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// FailureReason will be set in the event that there is a terminal problem
+	// reconciling the MinerSet.
+	// +optional
+	FailureReason *string `json:"failureReason,omitempty"`
+
+	// FailureMessage will be set in the event that there is a terminal problem
+	// reconciling the MinerSet.
+	// +optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+
+	// Conditions represent the latest available observations of the MinerSet's current state.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="MinersReady")].status`
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=`.status.conditions[?(@.type=="MinersReady")].reason`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// MinerSet is the Schema for the minersets API
+type MinerSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MinerSetSpec   `json:"spec,omitempty"`
+	Status MinerSetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MinerSetList contains a list of MinerSet
+type MinerSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MinerSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MinerSet{}, &MinerSetList{})
+}
@@ -20,15 +20,18 @@ import (
 	"crypto/tls"
 	"flag"
 	"os"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	crcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -36,7 +39,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	appsv1alpha1 "github.com/ashwinyue/minerx/api/v1alpha1"
+	appsv1beta1 "github.com/ashwinyue/minerx/api/v1beta1"
 	"github.com/ashwinyue/minerx/internal/controller"
+	webhookv1alpha1 "github.com/ashwinyue/minerx/internal/webhook/v1alpha1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -49,6 +54,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(appsv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(appsv1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -61,6 +67,11 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var minerSetDefaultReplicas int
+	var minerConcurrency, chainConcurrency, minerSetConcurrency int
+	var podGCInterval, chainCoalesceWindow time.Duration
+	var disableFinalizers bool
+	var genesisMinerLabels string
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -79,6 +90,27 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.IntVar(&minerSetDefaultReplicas, "minerset-default-replicas", int(appsv1alpha1.DefaultMinerSetReplicas),
+		"The number of replicas a MinerSet is defaulted to when none is specified.")
+	flag.IntVar(&minerConcurrency, "miner-concurrency", 1,
+		"The number of Miners that can be reconciled concurrently.")
+	flag.IntVar(&chainConcurrency, "chain-concurrency", 1,
+		"The number of Chains that can be reconciled concurrently.")
+	flag.IntVar(&minerSetConcurrency, "minerset-concurrency", 1,
+		"The number of MinerSets that can be reconciled concurrently.")
+	flag.DurationVar(&podGCInterval, "pod-gc-interval", controller.DefaultPodGCInterval,
+		"The interval between garbage collections of pods whose Miner no longer exists. Set to 0 to disable.")
+	flag.DurationVar(&chainCoalesceWindow, "chain-coalesce-window", controller.DefaultChainCoalesceWindow,
+		"How long Chain events are held back so that bursts of edits are reconciled once. Set to 0 to disable.")
+	// Finalizers show up as drift in GitOps setups that own the manifests of the objects. Without
+	// them, objects are deleted without waiting for the controllers, and the objects they own
+	// are cleaned up by garbage collection through their owner references.
+	flag.BoolVar(&disableFinalizers, "disable-finalizers", false,
+		"If set, the controllers do not add finalizers to the objects they reconcile and rely on "+
+			"garbage collection to clean up owned objects instead, e.g. for GitOps setups.")
+	flag.StringVar(&genesisMinerLabels, "genesis-miner-labels", "",
+		"Comma-separated key=value labels set on the genesis Miner of every Chain, e.g. app=miner, "+
+			"so that it can be selected by a MinerSet.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -178,27 +210,70 @@ func main() {
 		os.Exit(1)
 	}
 
+	genesisMinerLabelSet, err := labels.ConvertSelectorToLabelsMap(genesisMinerLabels)
+	if err != nil {
+		setupLog.Error(err, "invalid genesis miner labels", "labels", genesisMinerLabels)
+		os.Exit(1)
+	}
+
+	podLogReader, err := controller.NewPodLogReader(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create pod log reader")
+		os.Exit(1)
+	}
+
 	if err := (&controller.MinerReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		DisableFinalizers: disableFinalizers,
+		LogReader:         podLogReader,
+	}).SetupWithManager(mgr, crcontroller.Options{MaxConcurrentReconciles: minerConcurrency}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Miner")
 		os.Exit(1)
 	}
 	if err := (&controller.ChainReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+		Client:             mgr.GetClient(),
+		Scheme:             mgr.GetScheme(),
+		CoalesceWindow:     chainCoalesceWindow,
+		DisableFinalizers:  disableFinalizers,
+		GenesisMinerLabels: genesisMinerLabelSet,
+	}).SetupWithManager(mgr, crcontroller.Options{MaxConcurrentReconciles: chainConcurrency}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Chain")
 		os.Exit(1)
 	}
 	if err := (&controller.MinerSetReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		DisableFinalizers: disableFinalizers,
+	}).SetupWithManager(mgr, crcontroller.Options{MaxConcurrentReconciles: minerSetConcurrency}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MinerSet")
 		os.Exit(1)
 	}
+	if podGCInterval > 0 {
+		if err := (&controller.PodGarbageCollector{
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+			Interval:  podGCInterval,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to set up pod garbage collector")
+			os.Exit(1)
+		}
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err := webhookv1alpha1.SetupMinerSetWebhookWithManager(mgr, int32(minerSetDefaultReplicas)); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "MinerSet")
+			os.Exit(1)
+		}
+		if err := webhookv1alpha1.SetupChainWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Chain")
+			os.Exit(1)
+		}
+		if err := webhookv1alpha1.SetupMinerWebhookWithManager(mgr, appsv1alpha1.DefaultMinerResourceProfiles()); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Miner")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -19,15 +19,24 @@ package controller
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	appsv1alpha1 "github.com/ashwinyue/minerx/api/v1alpha1"
 	"github.com/ashwinyue/minerx/pkg/condition"
@@ -35,40 +44,88 @@ import (
 
 const (
 	chainFinalizer = "chain.onex.io/finalizer"
+
+	chainDeletionRequeueInterval = 2 * time.Second
+
+	// externalConfigMapRequeueInterval is how often to check for a ConfigMap referenced by
+	// spec.configMapName that does not exist yet.
+	externalConfigMapRequeueInterval = 10 * time.Second
+
+	// configMapConflictRequeueInterval is how often to check whether a ConfigMap carrying
+	// the chain label but controlled by another object is gone.
+	configMapConflictRequeueInterval = 30 * time.Second
+
+	// genesisMinerDeletionRequeueInterval is how often to check whether a terminating genesis
+	// miner is gone, so that it can be recreated under the same name.
+	genesisMinerDeletionRequeueInterval = 2 * time.Second
+
+	// minerConflictRequeueInterval is how often to check whether a Miner named after the
+	// chain but not managed by it is gone.
+	minerConflictRequeueInterval = 30 * time.Second
+
+	// minerLabelsAnnotation lists the keys of the chain miner labels last set on the genesis
+	// miner, so that labels removed from the chain are removed from the miner as well.
+	minerLabelsAnnotation = "chain.onex.io/miner-labels"
 )
 
 // ChainReconciler reconciles a Chain object
 type ChainReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// CoalesceWindow is how long requests triggered by watch events are held back so
+	// that bursts of edits are reconciled once. Zero disables coalescing.
+	CoalesceWindow time.Duration
+
+	// DisableFinalizers stops the controller from adding its finalizer, see --disable-finalizers.
+	DisableFinalizers bool
+
+	// GenesisMinerLabels are set on the genesis miner of every chain, so that it can be
+	// selected by label-based tooling such as a MinerSet. The miner labels of the chain
+	// take precedence over them.
+	GenesisMinerLabels map[string]string
 }
 
 // +kubebuilder:rbac:groups=apps.onex.io,resources=chains,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.onex.io,resources=chains/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps.onex.io,resources=chains/finalizers,verbs=update
 // +kubebuilder:rbac:groups=apps.onex.io,resources=miners,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps.onex.io,resources=minersets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 
-// SetupWithManager sets up the controller with the Manager.
-func (r *ChainReconciler) SetupWithManager(mgr ctrl.Manager) error {
+// SetupWithManager sets up the controller with the Manager. Changes to the owned genesis
+// miner, MinerSet and ConfigMap reconcile the chain, keeping its status current, and so do
+// changes to any miner carrying the chain label, which are counted in the chain status.
+// Unless a custom queue is configured, requests are coalesced over CoalesceWindow.
+func (r *ChainReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
+	if r.CoalesceWindow > 0 && options.NewQueue == nil && !ptr.Deref(options.UsePriorityQueue, false) {
+		options.NewQueue = newCoalescingQueue(r.CoalesceWindow)
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&appsv1alpha1.Chain{}).
+		Owns(&appsv1alpha1.Miner{}).
+		Owns(&appsv1alpha1.MinerSet{}).
+		Owns(&corev1.ConfigMap{}).
+		Watches(&appsv1alpha1.Miner{}, handler.EnqueueRequestsFromMapFunc(chainForMiner)).
+		WithOptions(options).
 		Complete(r)
 }
 
-func (r *ChainReconciler) lowestNonZeroResult(a, b ctrl.Result) ctrl.Result {
-	if a.RequeueAfter < b.RequeueAfter {
-		return a
-	}
-	if a.Requeue {
-		return a
+// chainForMiner maps a miner to the chain named by its chain label, if any.
+func chainForMiner(_ context.Context, obj client.Object) []reconcile.Request {
+	name := obj.GetLabels()[chainNameLabel]
+	if name == "" {
+		return nil
 	}
-	return b
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{Namespace: obj.GetNamespace(), Name: name}}}
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
-func (r *ChainReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *ChainReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	defer recoverReconcilePanic(ctx, "chain", req, &result, &err)
+
 	log := log.FromContext(ctx)
 
 	chain := &appsv1alpha1.Chain{}
@@ -93,6 +150,34 @@ func (r *ChainReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 func (r *ChainReconciler) reconcileDelete(ctx context.Context, chain *appsv1alpha1.Chain) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 
+	// Delete the miners first, the ConfigMap may still be needed until they are gone
+	minerSetsRemaining, err := r.deleteOwnedMinerSets(ctx, chain)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if minerSetsRemaining {
+		log.Info("Waiting for worker MinerSets of the Chain to be deleted")
+		return ctrl.Result{RequeueAfter: chainDeletionRequeueInterval}, nil
+	}
+
+	minersRemaining, err := r.deleteOwnedMiners(ctx, chain)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if minersRemaining {
+		log.Info("Waiting for Miners of the Chain to be deleted")
+		return ctrl.Result{RequeueAfter: chainDeletionRequeueInterval}, nil
+	}
+
+	configMapsRemaining, err := r.deleteOwnedConfigMaps(ctx, chain)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if configMapsRemaining {
+		log.Info("Waiting for ConfigMaps of the Chain to be deleted")
+		return ctrl.Result{RequeueAfter: chainDeletionRequeueInterval}, nil
+	}
+
 	if controllerutil.ContainsFinalizer(chain, chainFinalizer) {
 		controllerutil.RemoveFinalizer(chain, chainFinalizer)
 		if err := r.Update(ctx, chain); err != nil {
@@ -105,10 +190,103 @@ func (r *ChainReconciler) reconcileDelete(ctx context.Context, chain *appsv1alph
 	return ctrl.Result{}, nil
 }
 
+// deleteOwnedMiners deletes the miners controlled by the chain and returns true if
+// any of them still exists.
+func (r *ChainReconciler) deleteOwnedMiners(ctx context.Context, chain *appsv1alpha1.Chain) (bool, error) {
+	log := log.FromContext(ctx)
+
+	minerList := &appsv1alpha1.MinerList{}
+	if err := r.List(ctx, minerList, client.InNamespace(chain.Namespace), client.MatchingLabels{chainNameLabel: chain.Name}); err != nil {
+		log.Error(err, "Failed to list Miners")
+		return false, err
+	}
+
+	remaining := false
+	for i := range minerList.Items {
+		miner := &minerList.Items[i]
+		if !metav1.IsControlledBy(miner, chain) {
+			continue
+		}
+		remaining = true
+		if !miner.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err := r.Delete(ctx, miner); err != nil && !errors.IsNotFound(err) {
+			log.Error(err, "Failed to delete Miner", "miner", miner.Name)
+			return false, err
+		}
+		log.Info("Deleted Miner", "miner", miner.Name)
+	}
+
+	return remaining, nil
+}
+
+// deleteOwnedMinerSets deletes the worker MinerSets controlled by the chain and returns
+// true if any of them still exists.
+func (r *ChainReconciler) deleteOwnedMinerSets(ctx context.Context, chain *appsv1alpha1.Chain) (bool, error) {
+	log := log.FromContext(ctx)
+
+	msList := &appsv1alpha1.MinerSetList{}
+	if err := r.List(ctx, msList, client.InNamespace(chain.Namespace), client.MatchingLabels{chainNameLabel: chain.Name}); err != nil {
+		log.Error(err, "Failed to list MinerSets")
+		return false, err
+	}
+
+	remaining := false
+	for i := range msList.Items {
+		ms := &msList.Items[i]
+		if !metav1.IsControlledBy(ms, chain) {
+			continue
+		}
+		remaining = true
+		if !ms.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err := r.Delete(ctx, ms); err != nil && !errors.IsNotFound(err) {
+			log.Error(err, "Failed to delete MinerSet", "minerSet", ms.Name)
+			return false, err
+		}
+		log.Info("Deleted MinerSet", "minerSet", ms.Name)
+	}
+
+	return remaining, nil
+}
+
+// deleteOwnedConfigMaps deletes the ConfigMaps controlled by the chain and returns true
+// if any of them still exists.
+func (r *ChainReconciler) deleteOwnedConfigMaps(ctx context.Context, chain *appsv1alpha1.Chain) (bool, error) {
+	log := log.FromContext(ctx)
+
+	cmList := &corev1.ConfigMapList{}
+	if err := r.List(ctx, cmList, client.InNamespace(chain.Namespace), client.MatchingLabels{chainNameLabel: chain.Name}); err != nil {
+		log.Error(err, "Failed to list ConfigMaps")
+		return false, err
+	}
+
+	remaining := false
+	for i := range cmList.Items {
+		cm := &cmList.Items[i]
+		if !metav1.IsControlledBy(cm, chain) {
+			continue
+		}
+		remaining = true
+		if !cm.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err := r.Delete(ctx, cm); err != nil && !errors.IsNotFound(err) {
+			log.Error(err, "Failed to delete ConfigMap", "configMap", cm.Name)
+			return false, err
+		}
+		log.Info("Deleted ConfigMap", "configMap", cm.Name)
+	}
+
+	return remaining, nil
+}
+
 func (r *ChainReconciler) reconcile(ctx context.Context, chain *appsv1alpha1.Chain) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 
-	if !controllerutil.ContainsFinalizer(chain, chainFinalizer) {
+	if !r.DisableFinalizers && !controllerutil.ContainsFinalizer(chain, chainFinalizer) {
 		controllerutil.AddFinalizer(chain, chainFinalizer)
 		if err := r.Update(ctx, chain); err != nil {
 			log.Error(err, "Failed to add finalizer to Chain")
@@ -116,48 +294,146 @@ func (r *ChainReconciler) reconcile(ctx context.Context, chain *appsv1alpha1.Cha
 		}
 	}
 
+	observed := chain.Status.DeepCopy()
+
 	phases := []func(context.Context, *appsv1alpha1.Chain) (ctrl.Result, error){
 		r.reconcileConfigMap,
 		r.reconcileMiner,
+		r.reconcileWorkers,
+		r.reconcileMinerCounts,
+	}
+	if chain.Spec.Paused {
+		// Only observe the chain resources, unpausing triggers a reconcile that catches up
+		log.Info("Chain is paused, skipping the reconciliation of its resources")
+		condition.SetTrue(chain, condition.PausedCondition)
+		phases = []func(context.Context, *appsv1alpha1.Chain) (ctrl.Result, error){
+			r.reconcileMinerCounts,
+		}
+	} else {
+		condition.Delete(chain, condition.PausedCondition)
 	}
 
-	result := ctrl.Result{}
+	results := make([]ctrl.Result, 0, len(phases))
+	var terminalErr error
 	for _, phase := range phases {
 		phaseResult, err := phase(ctx, chain)
 		if err != nil {
-			log.Error(err, "Failed to execute reconciliation phase")
-			return ctrl.Result{}, err
+			reason, terminal := classifyChainError(err)
+			if !terminal {
+				// Transient errors are retried with the exponential backoff of the workqueue
+				log.Error(err, "Failed to execute reconciliation phase")
+				return ctrl.Result{}, err
+			}
+			// Retrying cannot help until the chain changes, which triggers a reconcile on its own
+			log.Error(err, "Reconciliation phase failed terminally", "reason", reason)
+			message := err.Error()
+			chain.Status.FailureReason = &reason
+			chain.Status.FailureMessage = &message
+			terminalErr = reconcile.TerminalError(err)
+			break
 		}
-		result = r.lowestNonZeroResult(result, phaseResult)
+		results = append(results, phaseResult)
 	}
+	if terminalErr == nil {
+		chain.Status.FailureReason = nil
+		chain.Status.FailureMessage = nil
+	}
+	result := aggregateResults(results...)
 
-	// Update status
+	// Update status, skipping the write when nothing changed
 	chain.Status.ObservedGeneration = chain.Generation
-	if err := r.Status().Update(ctx, chain); err != nil {
-		log.Error(err, "Failed to update Chain status")
-		return ctrl.Result{}, err
+	if !equality.Semantic.DeepEqual(observed, &chain.Status) {
+		if err := updateStatusWithRetry(ctx, r.Client, chain); err != nil {
+			log.Error(err, "Failed to update Chain status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if terminalErr != nil {
+		return ctrl.Result{}, terminalErr
 	}
 
 	log.Info("Chain reconciled successfully")
 	return result, nil
 }
 
+// classifyChainError returns the failure reason of an error returned by a reconcile phase,
+// and whether it is terminal. Errors are transient, such as a dependency that was not found
+// or a conflicting write, unless the API server rejected an object built from the chain
+// spec, which fails the same way until the spec changes.
+func classifyChainError(err error) (string, bool) {
+	if errors.IsInvalid(err) || errors.IsBadRequest(err) {
+		return string(condition.InvalidConfigurationReason), true
+	}
+	return "", false
+}
+
 func (r *ChainReconciler) reconcileConfigMap(ctx context.Context, chain *appsv1alpha1.Chain) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 
-	reconciled, err := r.IsConfigMapReconciled(ctx, chain)
-	if err != nil {
-		log.Error(err, "Failed to check if ConfigMap is reconciled")
+	if chain.Spec.ConfigMapName != nil {
+		return r.reconcileExternalConfigMap(ctx, chain)
+	}
+
+	cmList := &corev1.ConfigMapList{}
+	if err := r.List(ctx, cmList, client.InNamespace(chain.Namespace), client.MatchingLabels{chainNameLabel: chain.Name}); err != nil {
+		log.Error(err, "Failed to list ConfigMaps")
 		return ctrl.Result{}, err
 	}
-	if reconciled {
+
+	// A labeled ConfigMap controlled by another object is neither adopted nor deleted
+	for i := range cmList.Items {
+		cm := &cmList.Items[i]
+		if owner := metav1.GetControllerOf(cm); owner != nil && !metav1.IsControlledBy(cm, chain) {
+			log.Info("ConfigMap carrying the chain label is controlled by another object",
+				"configMap", cm.Name, "controllerKind", owner.Kind, "controller", owner.Name)
+			condition.MarkFalsef(chain, condition.ConfigMapsCreatedCondition, condition.ConfigMapConflictReason,
+				"ConfigMap %q carrying the chain label is controlled by %s %q", cm.Name, owner.Kind, owner.Name)
+			return ctrl.Result{RequeueAfter: configMapConflictRequeueInterval}, nil
+		}
+	}
+
+	// Keep exactly one ConfigMap per chain and remove any stray ones the chain controls,
+	// labeled ConfigMaps created by users are left alone
+	canonical := canonicalConfigMap(chain, cmList.Items)
+	for i := range cmList.Items {
+		cm := &cmList.Items[i]
+		if !metav1.IsControlledBy(cm, chain) || (canonical != nil && cm.Name == canonical.Name) {
+			continue
+		}
+		if err := r.Delete(ctx, cm); err != nil && !errors.IsNotFound(err) {
+			log.Error(err, "Failed to delete stray ConfigMap", "configMap", cm.Name)
+			return ctrl.Result{}, err
+		}
+		log.Info("Deleted stray ConfigMap", "configMap", cm.Name)
+	}
+
+	if canonical != nil {
+		chain.Status.ConfigMapRef = &appsv1alpha1.LocalObjectReference{Name: canonical.Name}
+		condition.SetTrue(chain, condition.ConfigMapsCreatedCondition)
+
+		// The drift is reported until the ConfigMap event of the fix reconciles the chain again
+		desired := configMapData(chain)
+		if !equality.Semantic.DeepEqual(canonical.Data, desired) {
+			patch := client.MergeFrom(canonical.DeepCopy())
+			canonical.Data = desired
+			if err := r.Patch(ctx, canonical, patch); err != nil {
+				log.Error(err, "Failed to update ConfigMap", "configMap", canonical.Name)
+				return ctrl.Result{}, err
+			}
+			log.Info("Updated drifted ConfigMap", "configMap", canonical.Name)
+			condition.MarkFalsef(chain, condition.ConfigMapUpToDateCondition, condition.ConfigMapDriftedReason,
+				"ConfigMap %q diverged from the desired content and was updated", canonical.Name)
+			return ctrl.Result{}, nil
+		}
+		condition.SetTrue(chain, condition.ConfigMapUpToDateCondition)
 		return ctrl.Result{}, nil
 	}
 
 	cm, err := r.createConfigMap(ctx, chain)
 	if err != nil {
 		log.Error(err, "Failed to create ConfigMap")
-		condition.SetFalse(chain, condition.ConfigMapsCreatedCondition, condition.FailedReason, fmt.Sprintf("Failed to create ConfigMap: %v", err))
+		condition.MarkFalsef(chain, condition.ConfigMapsCreatedCondition, condition.FailedReason, "Failed to create ConfigMap: %v", err)
 		return ctrl.Result{}, err
 	}
 
@@ -165,21 +441,55 @@ func (r *ChainReconciler) reconcileConfigMap(ctx context.Context, chain *appsv1a
 
 	log.Info("Created ConfigMap", "configMap", cm.Name)
 	condition.SetTrue(chain, condition.ConfigMapsCreatedCondition)
+	condition.SetTrue(chain, condition.ConfigMapUpToDateCondition)
 
 	return ctrl.Result{}, nil
 }
 
-func (r *ChainReconciler) IsConfigMapReconciled(ctx context.Context, chain *appsv1alpha1.Chain) (bool, error) {
+// reconcileExternalConfigMap points the chain at the user-provided ConfigMap named in
+// its spec, requeueing until that ConfigMap exists.
+func (r *ChainReconciler) reconcileExternalConfigMap(ctx context.Context, chain *appsv1alpha1.Chain) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 
-	cmList := &corev1.ConfigMapList{}
-	selectorMap := map[string]string{"chain.onex.io/name": chain.Name}
-	if err := r.List(ctx, cmList, client.InNamespace(chain.Namespace), client.MatchingLabels(selectorMap)); err != nil {
-		log.Error(err, "Failed to list ConfigMaps")
-		return false, err
+	name := *chain.Spec.ConfigMapName
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: chain.Namespace, Name: name}, cm); err != nil {
+		if !errors.IsNotFound(err) {
+			log.Error(err, "Failed to get ConfigMap", "configMap", name)
+			return ctrl.Result{}, err
+		}
+		log.Info("Referenced ConfigMap not found", "configMap", name)
+		chain.Status.ConfigMapRef = nil
+		condition.MarkFalsef(chain, condition.ConfigMapsCreatedCondition, condition.ConfigMapNotFoundReason,
+			"ConfigMap %q referenced by spec.configMapName not found", name)
+		return ctrl.Result{RequeueAfter: externalConfigMapRequeueInterval}, nil
 	}
 
-	return len(cmList.Items) != 0, nil
+	chain.Status.ConfigMapRef = &appsv1alpha1.LocalObjectReference{Name: cm.Name}
+	condition.SetTrue(chain, condition.ConfigMapsCreatedCondition)
+	// The content of a user-provided ConfigMap is not managed by the controller
+	condition.Delete(chain, condition.ConfigMapUpToDateCondition)
+	return ctrl.Result{}, nil
+}
+
+// canonicalConfigMap returns the ConfigMap the chain should keep among those carrying
+// its label: the one referenced by the chain status if it is controlled by the chain,
+// otherwise the oldest one controlled by the chain. It returns nil if there is none.
+func canonicalConfigMap(chain *appsv1alpha1.Chain, cms []corev1.ConfigMap) *corev1.ConfigMap {
+	var canonical *corev1.ConfigMap
+	for i := range cms {
+		cm := &cms[i]
+		if !metav1.IsControlledBy(cm, chain) {
+			continue
+		}
+		if chain.Status.ConfigMapRef != nil && cm.Name == chain.Status.ConfigMapRef.Name {
+			return cm
+		}
+		if canonical == nil || cm.CreationTimestamp.Before(&canonical.CreationTimestamp) {
+			canonical = cm
+		}
+	}
+	return canonical
 }
 
 func (r *ChainReconciler) reconcileMiner(ctx context.Context, chain *appsv1alpha1.Chain) (ctrl.Result, error) {
@@ -191,13 +501,20 @@ func (r *ChainReconciler) reconcileMiner(ctx context.Context, chain *appsv1alpha
 		return ctrl.Result{}, err
 	}
 	if reconciled {
+		if err := r.syncGenesisMinerLabels(ctx, chain); err != nil {
+			log.Error(err, "Failed to update the labels of the genesis Miner")
+			return ctrl.Result{}, err
+		}
 		return ctrl.Result{}, nil
 	}
 
 	miner, err := r.createMinerForChain(ctx, chain)
+	if errors.IsAlreadyExists(err) {
+		return r.reconcileExistingMiner(ctx, chain)
+	}
 	if err != nil {
 		log.Error(err, "Failed to create Miner")
-		condition.SetFalse(chain, condition.MinersCreatedCondition, condition.FailedReason, fmt.Sprintf("Failed to create Miner: %v", err))
+		condition.MarkFalsef(chain, condition.MinersCreatedCondition, condition.FailedReason, "Failed to create Miner: %v", err)
 		return ctrl.Result{}, err
 	}
 
@@ -211,17 +528,194 @@ func (r *ChainReconciler) reconcileMiner(ctx context.Context, chain *appsv1alpha
 	return ctrl.Result{}, nil
 }
 
+// reconcileExistingMiner handles a Miner named after the genesis miner that IsMinerReconciled
+// did not accept. A terminating one is waited for, so that the genesis miner is recreated
+// once it is gone. Any other one belongs to someone else and is reported as a conflict.
+func (r *ChainReconciler) reconcileExistingMiner(ctx context.Context, chain *appsv1alpha1.Chain) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	name := genesisMinerName(chain)
+	existing := &appsv1alpha1.Miner{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: chain.Namespace, Name: name}, existing); err != nil {
+		if errors.IsNotFound(err) {
+			// Deleted since the create, try again right away
+			return ctrl.Result{RequeueAfter: genesisMinerDeletionRequeueInterval}, nil
+		}
+		log.Error(err, "Failed to get Miner", "miner", name)
+		return ctrl.Result{}, err
+	}
+
+	if !existing.DeletionTimestamp.IsZero() {
+		log.Info("Waiting for the previous genesis Miner to be deleted", "miner", name)
+		condition.MarkFalsef(chain, condition.MinersCreatedCondition, condition.WaitingForMinerDeletionReason,
+			"Waiting for the previous genesis Miner %s to be deleted", name)
+		return ctrl.Result{RequeueAfter: genesisMinerDeletionRequeueInterval}, nil
+	}
+
+	log.Info("Miner named after the genesis miner is not managed by the Chain", "miner", name)
+	condition.MarkFalsef(chain, condition.MinersCreatedCondition, condition.MinerConflictReason,
+		"Miner %s exists and is not managed by the Chain", name)
+	return ctrl.Result{RequeueAfter: minerConflictRequeueInterval}, nil
+}
+
 func (r *ChainReconciler) IsMinerReconciled(ctx context.Context, chain *appsv1alpha1.Chain) (bool, error) {
 	log := log.FromContext(ctx)
 
 	mList := &appsv1alpha1.MinerList{}
-	selectorMap := map[string]string{"chain.onex.io/name": chain.Name}
+	selectorMap := map[string]string{chainNameLabel: chain.Name}
 	if err := r.List(ctx, mList, client.InNamespace(chain.Namespace), client.MatchingLabels(selectorMap)); err != nil {
 		log.Error(err, "Failed to list Miners")
 		return false, err
 	}
 
-	return len(mList.Items) != 0, nil
+	// Worker miners carry the chain label too, only count the ones the chain controls.
+	// A terminating genesis miner does not count, it has to be recreated once it is gone.
+	for i := range mList.Items {
+		miner := &mList.Items[i]
+		if !miner.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if metav1.IsControlledBy(miner, chain) {
+			return true, nil
+		}
+		// The genesis miner lost its owner reference, re-adopt it so it is deleted with the chain
+		if miner.Name == genesisMinerName(chain) && metav1.GetControllerOf(miner) == nil {
+			if err := r.adoptMiner(ctx, chain, miner); err != nil {
+				log.Error(err, "Failed to adopt Miner", "miner", miner.Name)
+				return false, err
+			}
+			log.Info("Adopted Miner", "miner", miner.Name)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *ChainReconciler) adoptMiner(ctx context.Context, chain *appsv1alpha1.Chain, miner *appsv1alpha1.Miner) error {
+	patch := client.MergeFrom(miner.DeepCopy())
+	miner.OwnerReferences = append(miner.OwnerReferences, *metav1.NewControllerRef(chain, chainKind))
+	return r.Patch(ctx, miner, patch)
+}
+
+// reconcileMinerCounts reports how many miners carry the chain label and how many of them are ready.
+func (r *ChainReconciler) reconcileMinerCounts(ctx context.Context, chain *appsv1alpha1.Chain) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	mList := &appsv1alpha1.MinerList{}
+	if err := r.List(ctx, mList, client.InNamespace(chain.Namespace), client.MatchingLabels{chainNameLabel: chain.Name}); err != nil {
+		log.Error(err, "Failed to list Miners")
+		return ctrl.Result{}, err
+	}
+
+	// Terminating miners are on their way out and are not counted, as in the MinerSet replicas
+	var count, ready int32
+	for i := range mList.Items {
+		if !mList.Items[i].DeletionTimestamp.IsZero() {
+			continue
+		}
+		count++
+		if mList.Items[i].Status.Ready {
+			ready++
+		}
+	}
+	chain.Status.MinerCount = count
+	chain.Status.ReadyMinerCount = ready
+
+	return ctrl.Result{}, nil
+}
+
+// reconcileWorkers creates or updates the MinerSet of the chain's worker miners, and
+// deletes it when the chain no longer asks for workers.
+func (r *ChainReconciler) reconcileWorkers(ctx context.Context, chain *appsv1alpha1.Chain) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	ms := &appsv1alpha1.MinerSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workerMinerSetName(chain),
+			Namespace: chain.Namespace,
+		},
+	}
+
+	if chain.Spec.WorkerReplicas == nil {
+		if chain.Status.MinerSetRef == nil {
+			return ctrl.Result{}, nil
+		}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(ms), ms); err != nil && !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		} else if err == nil && metav1.IsControlledBy(ms, chain) {
+			if err := r.Delete(ctx, ms); err != nil && !errors.IsNotFound(err) {
+				log.Error(err, "Failed to delete worker MinerSet", "minerSet", ms.Name)
+				return ctrl.Result{}, err
+			}
+			log.Info("Deleted worker MinerSet", "minerSet", ms.Name)
+		}
+		chain.Status.MinerSetRef = nil
+		return ctrl.Result{}, nil
+	}
+
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, ms, func() error {
+		if !ms.CreationTimestamp.IsZero() && !metav1.IsControlledBy(ms, chain) {
+			return fmt.Errorf("MinerSet %q exists and is not controlled by the chain", ms.Name)
+		}
+		setWorkerMinerSetSpec(chain, ms)
+		return controllerutil.SetControllerReference(chain, ms, r.Scheme)
+	})
+	if err != nil {
+		log.Error(err, "Failed to reconcile worker MinerSet", "minerSet", ms.Name)
+		condition.MarkFalsef(chain, condition.MinersCreatedCondition, condition.FailedReason, "Failed to reconcile worker MinerSet: %v", err)
+		return ctrl.Result{}, err
+	}
+	if op != controllerutil.OperationResultNone {
+		log.Info("Reconciled worker MinerSet", "minerSet", ms.Name, "operation", op)
+	}
+
+	chain.Status.MinerSetRef = &appsv1alpha1.LocalObjectReference{Name: ms.Name}
+	return ctrl.Result{}, nil
+}
+
+// workerMinerSetName returns the name of the MinerSet of the chain's worker miners.
+func workerMinerSetName(chain *appsv1alpha1.Chain) string {
+	return fmt.Sprintf("%s-workers", chain.Name)
+}
+
+// setWorkerMinerSetSpec sets the labels and spec of the worker MinerSet of the chain. The
+// selector and template labels follow the ones computeDesiredMiner puts on miners, so they
+// never match the genesis miner. A stored template that only differs from the desired one
+// by its defaulted fields is kept, so that an unchanged chain does not update the MinerSet.
+func setWorkerMinerSetSpec(chain *appsv1alpha1.Chain, ms *appsv1alpha1.MinerSet) {
+	selector := map[string]string{
+		chainNameLabel:    chain.Name,
+		minerSetNameLabel: ms.Name,
+	}
+
+	template := appsv1alpha1.MinerTemplateSpec{
+		Spec: appsv1alpha1.MinerSpec{
+			MinerType:     appsv1alpha1.MinerType(chain.Spec.MinerType),
+			RestartPolicy: corev1.RestartPolicyAlways,
+		},
+	}
+	if chain.Spec.WorkerTemplate != nil {
+		template = *chain.Spec.WorkerTemplate.DeepCopy()
+	}
+	template.Spec.ChainName = chain.Name
+	if template.Labels == nil {
+		template.Labels = make(map[string]string, len(selector))
+	}
+	for k, v := range selector {
+		template.Labels[k] = v
+	}
+
+	if ms.Labels == nil {
+		ms.Labels = make(map[string]string)
+	}
+	ms.Labels[chainNameLabel] = chain.Name
+
+	ms.Spec.Replicas = ptr.To(*chain.Spec.WorkerReplicas)
+	ms.Spec.Selector = metav1.LabelSelector{MatchLabels: selector}
+	if !equality.Semantic.DeepEqual(ms.Spec.Template.ObjectMeta, template.ObjectMeta) ||
+		!appsv1alpha1.MinerSpecSemanticEqual(ms.Spec.Template.Spec, template.Spec) {
+		ms.Spec.Template = template
+	}
 }
 
 func (r *ChainReconciler) createConfigMap(ctx context.Context, chain *appsv1alpha1.Chain) (*corev1.ConfigMap, error) {
@@ -229,26 +723,74 @@ func (r *ChainReconciler) createConfigMap(ctx context.Context, chain *appsv1alph
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: fmt.Sprintf("%s-", chain.Name),
 			Namespace:    chain.Namespace,
-			Labels:       map[string]string{"chain.onex.io/name": chain.Name},
+			Labels:       map[string]string{chainNameLabel: chain.Name},
 			OwnerReferences: []metav1.OwnerReference{
 				*metav1.NewControllerRef(chain, chainKind),
 			},
 		},
-		Data: map[string]string{
-			"chainName": chain.Name,
-			"image":     chain.Spec.Image,
-		},
+		Data: configMapData(chain),
+	}
+
+	if err := r.Create(ctx, cm); err != nil {
+		return nil, err
 	}
 
 	return cm, nil
 }
 
+// configMapData returns the data of the chain ConfigMap. Extra configuration from the
+// chain spec is merged in, but cannot override the keys managed by the controller.
+func configMapData(chain *appsv1alpha1.Chain) map[string]string {
+	data := make(map[string]string, len(chain.Spec.ExtraConfig)+2)
+	for k, v := range chain.Spec.ExtraConfig {
+		data[k] = v
+	}
+	data["chainName"] = chain.Name
+	data["image"] = chain.Spec.Image
+	return data
+}
+
+// genesisMinerName returns the name the chain controller gives to the genesis miner of the chain.
+func genesisMinerName(chain *appsv1alpha1.Chain) string {
+	return chain.Name
+}
+
+// GetGenesisMiner returns the genesis miner of the chain. It is the miner referenced by the
+// chain status, or the miner named after the chain while the status does not reference one.
+func GetGenesisMiner(ctx context.Context, c client.Client, chain *appsv1alpha1.Chain) (*appsv1alpha1.Miner, error) {
+	name := genesisMinerName(chain)
+	if chain.Status.MinerRef != nil && chain.Status.MinerRef.Name != "" {
+		name = chain.Status.MinerRef.Name
+	}
+
+	miner := &appsv1alpha1.Miner{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: chain.Namespace, Name: name}, miner); err != nil {
+		return nil, err
+	}
+	return miner, nil
+}
+
 func (r *ChainReconciler) createMinerForChain(ctx context.Context, chain *appsv1alpha1.Chain) (*appsv1alpha1.Miner, error) {
+	labels := make(map[string]string, len(r.GenesisMinerLabels)+len(chain.Spec.MinerLabels)+1)
+	for k, v := range r.GenesisMinerLabels {
+		labels[k] = v
+	}
+	for k, v := range chain.Spec.MinerLabels {
+		labels[k] = v
+	}
+	labels[chainNameLabel] = chain.Name
+
+	var annotations map[string]string
+	if keys := minerLabelKeys(chain); keys != "" {
+		annotations = map[string]string{minerLabelsAnnotation: keys}
+	}
+
 	miner := &appsv1alpha1.Miner{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      chain.Name,
-			Namespace: chain.Namespace,
-			Labels:    map[string]string{"chain.onex.io/name": chain.Name},
+			Name:        genesisMinerName(chain),
+			Namespace:   chain.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
 			OwnerReferences: []metav1.OwnerReference{
 				*metav1.NewControllerRef(chain, chainKind),
 			},
@@ -260,7 +802,69 @@ func (r *ChainReconciler) createMinerForChain(ctx context.Context, chain *appsv1
 		},
 	}
 
+	if err := r.Create(ctx, miner); err != nil {
+		return nil, err
+	}
+
 	return miner, nil
 }
 
+// syncGenesisMinerLabels applies changes of the miner labels of the chain to its existing
+// genesis miner. A label removed from the chain is removed from the miner, or reset to its
+// GenesisMinerLabels value. The pod of the miner keeps the labels it was created with.
+func (r *ChainReconciler) syncGenesisMinerLabels(ctx context.Context, chain *appsv1alpha1.Chain) error {
+	miner := &appsv1alpha1.Miner{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: chain.Namespace, Name: genesisMinerName(chain)}, miner); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(miner, chain) || !miner.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	original := miner.DeepCopy()
+	if miner.Labels == nil {
+		miner.Labels = make(map[string]string)
+	}
+	if previous := miner.Annotations[minerLabelsAnnotation]; previous != "" {
+		for _, k := range strings.Split(previous, ",") {
+			if _, ok := chain.Spec.MinerLabels[k]; ok || k == chainNameLabel {
+				continue
+			}
+			if v, ok := r.GenesisMinerLabels[k]; ok {
+				miner.Labels[k] = v
+			} else {
+				delete(miner.Labels, k)
+			}
+		}
+	}
+	for k, v := range chain.Spec.MinerLabels {
+		if k != chainNameLabel {
+			miner.Labels[k] = v
+		}
+	}
+
+	if keys := minerLabelKeys(chain); keys != "" {
+		if miner.Annotations == nil {
+			miner.Annotations = make(map[string]string)
+		}
+		miner.Annotations[minerLabelsAnnotation] = keys
+	} else {
+		delete(miner.Annotations, minerLabelsAnnotation)
+	}
+
+	if equality.Semantic.DeepEqual(original.Labels, miner.Labels) &&
+		equality.Semantic.DeepEqual(original.Annotations, miner.Annotations) {
+		return nil
+	}
+	return r.Patch(ctx, miner, client.MergeFrom(original))
+}
+
+// minerLabelKeys returns the sorted, comma separated keys of the miner labels of the chain
+// recorded in the minerLabelsAnnotation of its genesis miner.
+func minerLabelKeys(chain *appsv1alpha1.Chain) string {
+	keys := slices.Sorted(maps.Keys(chain.Spec.MinerLabels))
+	keys = slices.DeleteFunc(keys, func(k string) bool { return k == chainNameLabel })
+	return strings.Join(keys, ",")
+}
+
 var chainKind = appsv1alpha1.GroupVersion.WithKind("Chain")
@@ -18,16 +18,27 @@ package controller
 
 import (
 	"context"
+	"fmt"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	appsv1alpha1 "github.com/ashwinyue/minerx/api/v1alpha1"
+	"github.com/ashwinyue/minerx/pkg/condition"
 )
 
 var _ = Describe("Chain Controller", func() {
@@ -81,4 +92,1267 @@ var _ = Describe("Chain Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When reconciling ConfigMaps", func() {
+		const resourceName = "test-chain-configmap"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			By("creating the custom resource for the Kind Chain")
+			resource := &appsv1alpha1.Chain{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: appsv1alpha1.ChainSpec{
+					MinerType: "small",
+					Image:     "nginx:alpine",
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &appsv1alpha1.Chain{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				resource.Finalizers = nil
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+
+			By("Cleaning up ConfigMaps")
+			Expect(k8sClient.DeleteAllOf(ctx, &corev1.ConfigMap{}, client.InNamespace("default"),
+				client.MatchingLabels{chainNameLabel: resourceName})).To(Succeed())
+			Expect(k8sClient.DeleteAllOf(ctx, &appsv1alpha1.Miner{}, client.InNamespace("default"),
+				client.MatchingLabels{chainNameLabel: resourceName})).To(Succeed())
+		})
+
+		It("should not write the Chain when nothing changed", func() {
+			By("Reconciling until the chain resources and status settle")
+			controllerReconciler := &ChainReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			chain := &appsv1alpha1.Chain{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			resourceVersion := chain.ResourceVersion
+
+			By("Reconciling again without any change")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			Expect(chain.ResourceVersion).To(Equal(resourceVersion))
+		})
+
+		It("should stop retrying and report a terminal failure", func() {
+			By("Reconciling with a ConfigMap the API server rejects")
+			controllerReconciler := &ChainReconciler{
+				Client: &failingConfigMapClient{Client: k8sClient, err: errors.NewInvalid(
+					corev1.SchemeGroupVersion.WithKind("ConfigMap").GroupKind(), resourceName,
+					field.ErrorList{field.TooLong(field.NewPath("data"), "", 1048576)})},
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).To(MatchError(reconcile.TerminalError(nil)))
+
+			By("Checking the failure is reported on the chain status")
+			chain := &appsv1alpha1.Chain{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			Expect(chain.Status.FailureReason).To(Equal(ptr.To(string(condition.InvalidConfigurationReason))))
+			Expect(chain.Status.FailureMessage).NotTo(BeNil())
+			Expect(*chain.Status.FailureMessage).To(ContainSubstring("ConfigMap"))
+			Expect(condition.IsFalse(chain, condition.ConfigMapsCreatedCondition)).To(BeTrue())
+		})
+
+		It("should clear the failure fields once the chain recovers", func() {
+			By("Failing the ConfigMap creation terminally")
+			controllerReconciler := &ChainReconciler{
+				Client: &failingConfigMapClient{Client: k8sClient, err: errors.NewBadRequest("rejected ConfigMap")},
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).To(HaveOccurred())
+
+			chain := &appsv1alpha1.Chain{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			Expect(chain.Status.FailureReason).To(Equal(ptr.To(string(condition.InvalidConfigurationReason))))
+			Expect(chain.Status.FailureMessage).To(Equal(ptr.To("rejected ConfigMap")))
+
+			By("Reconciling once the ConfigMap can be created")
+			controllerReconciler.Client = k8sClient
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			Expect(chain.Status.FailureReason).To(BeNil())
+			Expect(chain.Status.FailureMessage).To(BeNil())
+			Expect(chain.Status.ConfigMapRef).NotTo(BeNil())
+		})
+
+		It("should retry a transient failure without reporting it as terminal", func() {
+			By("Reconciling with a ConfigMap create that fails transiently")
+			controllerReconciler := &ChainReconciler{
+				Client: &failingConfigMapClient{Client: k8sClient, err: errors.NewNotFound(
+					corev1.Resource("namespaces"), "default")},
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err).NotTo(MatchError(reconcile.TerminalError(nil)))
+
+			chain := &appsv1alpha1.Chain{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			Expect(chain.Status.FailureReason).To(BeNil())
+			Expect(chain.Status.FailureMessage).To(BeNil())
+		})
+
+		It("should merge extra config into the ConfigMap without overriding reserved keys", func() {
+			By("Setting extra config on the Chain")
+			chain := &appsv1alpha1.Chain{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			chain.Spec.ExtraConfig = map[string]string{
+				"networkId": "1337",
+				"chainName": "clobbered",
+				"image":     "clobbered",
+			}
+			Expect(k8sClient.Update(ctx, chain)).To(Succeed())
+
+			By("Reconciling the Chain")
+			controllerReconciler := &ChainReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the ConfigMap data")
+			cmList := &corev1.ConfigMapList{}
+			Expect(k8sClient.List(ctx, cmList, client.InNamespace("default"),
+				client.MatchingLabels{chainNameLabel: resourceName})).To(Succeed())
+			Expect(cmList.Items).To(HaveLen(1))
+			Expect(cmList.Items[0].Data).To(HaveKeyWithValue("networkId", "1337"))
+			Expect(cmList.Items[0].Data).To(HaveKeyWithValue("chainName", resourceName))
+			Expect(cmList.Items[0].Data).To(HaveKeyWithValue("image", "nginx:alpine"))
+		})
+
+		It("should report and fix ConfigMap content edited outside the controller", func() {
+			controllerReconciler := &ChainReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			reconcileChain := func() *appsv1alpha1.Chain {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				chain := &appsv1alpha1.Chain{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+				return chain
+			}
+
+			By("Reconciling the Chain")
+			chain := reconcileChain()
+			Expect(condition.IsTrue(chain, condition.ConfigMapUpToDateCondition)).To(BeTrue())
+
+			By("Editing the ConfigMap")
+			cm := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: chain.Status.ConfigMapRef.Name, Namespace: "default"}, cm)).To(Succeed())
+			cm.Data["image"] = "edited"
+			cm.Data["extra"] = "edited"
+			Expect(k8sClient.Update(ctx, cm)).To(Succeed())
+
+			By("Checking the drift is reported and the ConfigMap restored")
+			chain = reconcileChain()
+			cond := condition.Get(chain, condition.ConfigMapUpToDateCondition)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(string(condition.ConfigMapDriftedReason)))
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cm), cm)).To(Succeed())
+			Expect(cm.Data).To(Equal(configMapData(chain)))
+
+			By("Checking the condition recovers")
+			chain = reconcileChain()
+			Expect(condition.IsTrue(chain, condition.ConfigMapUpToDateCondition)).To(BeTrue())
+		})
+
+		It("should remove stray ConfigMaps controlled by the Chain", func() {
+			controllerReconciler := &ChainReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			By("Reconciling the Chain to create its ConfigMap")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			chain := &appsv1alpha1.Chain{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			Expect(chain.Status.ConfigMapRef).NotTo(BeNil())
+
+			By("Creating a stray labeled ConfigMap controlled by the Chain")
+			stray := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "stray-configmap",
+					Namespace:       "default",
+					Labels:          map[string]string{chainNameLabel: resourceName},
+					OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(chain, chainKind)},
+				},
+			}
+			Expect(k8sClient.Create(ctx, stray)).To(Succeed())
+
+			By("Creating a labeled ConfigMap owned by nobody")
+			userOwned := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "user-configmap",
+					Namespace: "default",
+					Labels:    map[string]string{chainNameLabel: resourceName},
+				},
+			}
+			Expect(k8sClient.Create(ctx, userOwned)).To(Succeed())
+
+			By("Reconciling the Chain again")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the stray ConfigMap was removed")
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(stray), &corev1.ConfigMap{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			By("Checking the ConfigMap owned by nobody was kept")
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(userOwned), &corev1.ConfigMap{})).To(Succeed())
+
+			By("Checking exactly one ConfigMap controlled by the Chain remains")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			cmList := &corev1.ConfigMapList{}
+			Expect(k8sClient.List(ctx, cmList, client.InNamespace("default"),
+				client.MatchingLabels{chainNameLabel: resourceName})).To(Succeed())
+			var controlled []string
+			for i := range cmList.Items {
+				if metav1.IsControlledBy(&cmList.Items[i], chain) {
+					controlled = append(controlled, cmList.Items[i].Name)
+				}
+			}
+			Expect(controlled).To(HaveLen(1))
+
+			Expect(chain.Status.ConfigMapRef).NotTo(BeNil())
+			Expect(chain.Status.ConfigMapRef.Name).To(Equal(controlled[0]))
+		})
+
+		It("should report a labeled ConfigMap controlled by another object", func() {
+			By("Creating a labeled ConfigMap controlled by another Chain")
+			other := &appsv1alpha1.Chain{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "other-chain",
+					Namespace: "default",
+				},
+				Spec: appsv1alpha1.ChainSpec{
+					MinerType: "small",
+					Image:     "nginx:alpine",
+				},
+			}
+			Expect(k8sClient.Create(ctx, other)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, other)).To(Succeed())
+			})
+			foreign := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "foreign-configmap",
+					Namespace:       "default",
+					Labels:          map[string]string{chainNameLabel: resourceName},
+					OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(other, chainKind)},
+				},
+			}
+			Expect(k8sClient.Create(ctx, foreign)).To(Succeed())
+
+			By("Reconciling the Chain")
+			controllerReconciler := &ChainReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(configMapConflictRequeueInterval))
+
+			By("Checking the ConfigMap was neither adopted nor deleted")
+			cm := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(foreign), cm)).To(Succeed())
+			Expect(metav1.IsControlledBy(cm, other)).To(BeTrue())
+
+			By("Checking the conflict is reported")
+			chain := &appsv1alpha1.Chain{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			Expect(chain.Status.ConfigMapRef).To(BeNil())
+			cond := condition.Get(chain, condition.ConfigMapsCreatedCondition)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(string(condition.ConfigMapConflictReason)))
+		})
+
+		It("should consume an externally provided ConfigMap instead of creating one", func() {
+			By("Creating an external ConfigMap")
+			external := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "external-chain-config",
+					Namespace: "default",
+				},
+				Data: map[string]string{"chainName": resourceName},
+			}
+			Expect(k8sClient.Create(ctx, external)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, external)).To(Succeed())
+			})
+
+			By("Referencing the ConfigMap from the Chain")
+			chain := &appsv1alpha1.Chain{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			chain.Spec.ConfigMapName = ptr.To(external.Name)
+			Expect(k8sClient.Update(ctx, chain)).To(Succeed())
+
+			By("Reconciling the Chain")
+			controllerReconciler := &ChainReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking no ConfigMap was created for the Chain")
+			cmList := &corev1.ConfigMapList{}
+			Expect(k8sClient.List(ctx, cmList, client.InNamespace("default"),
+				client.MatchingLabels{chainNameLabel: resourceName})).To(Succeed())
+			Expect(cmList.Items).To(BeEmpty())
+
+			By("Checking the Chain references the external ConfigMap")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			Expect(chain.Status.ConfigMapRef).NotTo(BeNil())
+			Expect(chain.Status.ConfigMapRef.Name).To(Equal(external.Name))
+			Expect(condition.IsTrue(chain, condition.ConfigMapsCreatedCondition)).To(BeTrue())
+		})
+
+		It("should report a missing external ConfigMap", func() {
+			By("Referencing a ConfigMap that does not exist")
+			chain := &appsv1alpha1.Chain{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			chain.Spec.ConfigMapName = ptr.To("missing-chain-config")
+			Expect(k8sClient.Update(ctx, chain)).To(Succeed())
+
+			By("Reconciling the Chain")
+			controllerReconciler := &ChainReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the requeue of the ConfigMap phase is kept by the later phases")
+			Expect(result.RequeueAfter).To(Equal(externalConfigMapRequeueInterval))
+
+			By("Checking the ConfigMapsCreated condition")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			Expect(chain.Status.ConfigMapRef).To(BeNil())
+			Expect(condition.IsFalse(chain, condition.ConfigMapsCreatedCondition)).To(BeTrue())
+			Expect(condition.Get(chain, condition.ConfigMapsCreatedCondition).Reason).To(Equal(string(condition.ConfigMapNotFoundReason)))
+		})
+	})
+
+	Context("When reconciling workers", func() {
+		const resourceName = "test-chain-workers"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			By("creating a Chain with worker replicas")
+			resource := &appsv1alpha1.Chain{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: appsv1alpha1.ChainSpec{
+					MinerType:      "small",
+					Image:          "nginx:alpine",
+					WorkerReplicas: ptr.To(int32(2)),
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &appsv1alpha1.Chain{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				resource.Finalizers = nil
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+
+			By("Cleaning up owned resources")
+			Expect(k8sClient.DeleteAllOf(ctx, &appsv1alpha1.MinerSet{}, client.InNamespace("default"),
+				client.MatchingLabels{chainNameLabel: resourceName})).To(Succeed())
+			Expect(k8sClient.DeleteAllOf(ctx, &corev1.ConfigMap{}, client.InNamespace("default"),
+				client.MatchingLabels{chainNameLabel: resourceName})).To(Succeed())
+			Expect(k8sClient.DeleteAllOf(ctx, &appsv1alpha1.Miner{}, client.InNamespace("default"),
+				client.MatchingLabels{chainNameLabel: resourceName})).To(Succeed())
+		})
+
+		It("should create a worker MinerSet owned by the Chain", func() {
+			By("Reconciling the Chain")
+			controllerReconciler := &ChainReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the worker MinerSet")
+			chain := &appsv1alpha1.Chain{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			Expect(chain.Status.MinerSetRef).NotTo(BeNil())
+
+			ms := &appsv1alpha1.MinerSet{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: chain.Status.MinerSetRef.Name, Namespace: "default"}, ms)).To(Succeed())
+			Expect(metav1.IsControlledBy(ms, chain)).To(BeTrue())
+			Expect(*ms.Spec.Replicas).To(Equal(int32(2)))
+			Expect(ms.Spec.Template.Spec.ChainName).To(Equal(resourceName))
+			Expect(ms.Spec.Template.Labels).To(HaveKeyWithValue(chainNameLabel, resourceName))
+			Expect(ms.Spec.Selector.MatchLabels).To(HaveKeyWithValue(minerSetNameLabel, ms.Name))
+
+			By("Checking the genesis Miner is distinct from the workers")
+			Expect(chain.Status.MinerRef).NotTo(BeNil())
+			Expect(chain.Status.MinerRef.Name).NotTo(Equal(ms.Name))
+		})
+
+		It("should not update an unchanged worker MinerSet", func() {
+			By("Reconciling the Chain to create the worker MinerSet")
+			counting := &minerSetUpdateCountingClient{Client: k8sClient}
+			controllerReconciler := &ChainReconciler{
+				Client: counting,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Reconciling the unchanged Chain again")
+			counting.updates = 0
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counting.updates).To(BeZero())
+		})
+	})
+
+	Context("When applying miner labels", func() {
+		const resourceName = "test-chain-miner-labels"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			By("creating a Chain with custom miner labels")
+			resource := &appsv1alpha1.Chain{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: appsv1alpha1.ChainSpec{
+					MinerType: "small",
+					Image:     "nginx:alpine",
+					MinerLabels: map[string]string{
+						"team":         "mining",
+						chainNameLabel: "other-chain",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &appsv1alpha1.Chain{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				resource.Finalizers = nil
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+
+			By("Cleaning up owned resources")
+			Expect(k8sClient.DeleteAllOf(ctx, &corev1.ConfigMap{}, client.InNamespace("default"),
+				client.MatchingLabels{chainNameLabel: resourceName})).To(Succeed())
+			Expect(k8sClient.DeleteAllOf(ctx, &appsv1alpha1.Miner{}, client.InNamespace("default"),
+				client.MatchingLabels{chainNameLabel: resourceName})).To(Succeed())
+		})
+
+		It("should set the custom labels on the genesis Miner", func() {
+			By("Reconciling the Chain")
+			controllerReconciler := &ChainReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the genesis Miner labels")
+			chain := &appsv1alpha1.Chain{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+
+			miner, err := GetGenesisMiner(ctx, k8sClient, chain)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(miner.Labels).To(HaveKeyWithValue("team", "mining"))
+			Expect(miner.Labels).To(HaveKeyWithValue(chainNameLabel, resourceName))
+		})
+
+		It("should apply changed miner labels to the existing genesis Miner", func() {
+			By("Reconciling the Chain")
+			controllerReconciler := &ChainReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				GenesisMinerLabels: map[string]string{
+					"team": "platform",
+				},
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Changing the miner labels of the Chain")
+			chain := &appsv1alpha1.Chain{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			chain.Spec.MinerLabels = map[string]string{"tier": "gold"}
+			Expect(k8sClient.Update(ctx, chain)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the genesis Miner labels follow the Chain")
+			miner, err := GetGenesisMiner(ctx, k8sClient, chain)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(miner.Labels).To(HaveKeyWithValue("tier", "gold"))
+			Expect(miner.Labels).To(HaveKeyWithValue("team", "platform"))
+			Expect(miner.Labels).To(HaveKeyWithValue(chainNameLabel, resourceName))
+
+			By("Removing the miner labels of the Chain")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			chain.Spec.MinerLabels = nil
+			Expect(k8sClient.Update(ctx, chain)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			miner, err = GetGenesisMiner(ctx, k8sClient, chain)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(miner.Labels).NotTo(HaveKey("tier"))
+			Expect(miner.Labels).To(HaveKeyWithValue("team", "platform"))
+			Expect(miner.Labels).To(HaveKeyWithValue(chainNameLabel, resourceName))
+			Expect(miner.Annotations).NotTo(HaveKey(minerLabelsAnnotation))
+		})
+
+		It("should set the configured genesis miner labels on the genesis Miner", func() {
+			By("Reconciling the Chain with genesis miner labels")
+			controllerReconciler := &ChainReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				GenesisMinerLabels: map[string]string{
+					"app":          "miner",
+					"team":         "platform",
+					chainNameLabel: "other-chain",
+				},
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the genesis Miner labels")
+			chain := &appsv1alpha1.Chain{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+
+			miner, err := GetGenesisMiner(ctx, k8sClient, chain)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(miner.Labels).To(HaveKeyWithValue("app", "miner"))
+			Expect(miner.Labels).To(HaveKeyWithValue("team", "mining"))
+			Expect(miner.Labels).To(HaveKeyWithValue(chainNameLabel, resourceName))
+
+			By("Checking a MinerSet selector matches the genesis Miner")
+			selector := labels.SelectorFromSet(labels.Set{"app": "miner"})
+			Expect(selector.Matches(labels.Set(miner.Labels))).To(BeTrue())
+		})
+
+		It("should re-adopt the genesis Miner after its owner reference is removed", func() {
+			By("Reconciling the Chain")
+			controllerReconciler := &ChainReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Removing the owner reference from the genesis Miner")
+			chain := &appsv1alpha1.Chain{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			miner, err := GetGenesisMiner(ctx, k8sClient, chain)
+			Expect(err).NotTo(HaveOccurred())
+			miner.OwnerReferences = nil
+			Expect(k8sClient.Update(ctx, miner)).To(Succeed())
+
+			By("Reconciling the Chain again")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the owner reference is restored")
+			miner, err = GetGenesisMiner(ctx, k8sClient, chain)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metav1.IsControlledBy(miner, chain)).To(BeTrue())
+		})
+
+		It("should report a conflict with a Miner named after the Chain that it does not manage", func() {
+			By("Creating a Miner named after the Chain without the chain label")
+			existing := &appsv1alpha1.Miner{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec:       appsv1alpha1.MinerSpec{ChainName: "other-chain"},
+			}
+			Expect(k8sClient.Create(ctx, existing)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, existing))).To(Succeed())
+			})
+
+			By("Reconciling the Chain")
+			controllerReconciler := &ChainReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(minerConflictRequeueInterval))
+
+			By("Checking the conflict is reported and the Miner is left alone")
+			chain := &appsv1alpha1.Chain{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			cond := condition.Get(chain, condition.MinersCreatedCondition)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(string(condition.MinerConflictReason)))
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(existing), existing)).To(Succeed())
+			Expect(metav1.GetControllerOf(existing)).To(BeNil())
+		})
+
+		It("should wait for a deleted genesis Miner to be gone before recreating it", func() {
+			By("Reconciling the Chain")
+			controllerReconciler := &ChainReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Deleting the genesis Miner while a finalizer keeps it terminating")
+			chain := &appsv1alpha1.Chain{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			miner, err := GetGenesisMiner(ctx, k8sClient, chain)
+			Expect(err).NotTo(HaveOccurred())
+			oldUID := miner.UID
+			controllerutil.AddFinalizer(miner, minerFinalizer)
+			Expect(k8sClient.Update(ctx, miner)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, miner)).To(Succeed())
+
+			By("Reconciling the Chain while the genesis Miner is terminating")
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			cond := condition.Get(chain, condition.MinersCreatedCondition)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(string(condition.WaitingForMinerDeletionReason)))
+
+			By("Removing the finalizer so the genesis Miner is gone")
+			miner, err = GetGenesisMiner(ctx, k8sClient, chain)
+			Expect(err).NotTo(HaveOccurred())
+			controllerutil.RemoveFinalizer(miner, minerFinalizer)
+			Expect(k8sClient.Update(ctx, miner)).To(Succeed())
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(miner), &appsv1alpha1.Miner{}))
+			}).Should(BeTrue())
+
+			By("Reconciling the Chain after the genesis Miner is gone")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			Expect(condition.IsTrue(chain, condition.MinersCreatedCondition)).To(BeTrue())
+			miner, err = GetGenesisMiner(ctx, k8sClient, chain)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(miner.UID).NotTo(Equal(oldUID))
+			Expect(metav1.IsControlledBy(miner, chain)).To(BeTrue())
+		})
+	})
+
+	Context("When pausing a Chain", func() {
+		const resourceName = "test-chain-paused"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			By("creating a paused Chain")
+			resource := &appsv1alpha1.Chain{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: appsv1alpha1.ChainSpec{
+					MinerType: "small",
+					Image:     "nginx:alpine",
+					Paused:    true,
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &appsv1alpha1.Chain{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				resource.Finalizers = nil
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+
+			By("Cleaning up owned resources")
+			Expect(k8sClient.DeleteAllOf(ctx, &corev1.ConfigMap{}, client.InNamespace("default"),
+				client.MatchingLabels{chainNameLabel: resourceName})).To(Succeed())
+			Expect(k8sClient.DeleteAllOf(ctx, &appsv1alpha1.Miner{}, client.InNamespace("default"),
+				client.MatchingLabels{chainNameLabel: resourceName})).To(Succeed())
+		})
+
+		It("should not create resources until the Chain is unpaused", func() {
+			By("Reconciling the paused Chain")
+			controllerReconciler := &ChainReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking no resources were created")
+			chain := &appsv1alpha1.Chain{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			Expect(condition.IsTrue(chain, condition.PausedCondition)).To(BeTrue())
+			Expect(chain.Status.ConfigMapRef).To(BeNil())
+			Expect(chain.Status.MinerRef).To(BeNil())
+
+			cmList := &corev1.ConfigMapList{}
+			Expect(k8sClient.List(ctx, cmList, client.InNamespace("default"),
+				client.MatchingLabels{chainNameLabel: resourceName})).To(Succeed())
+			Expect(cmList.Items).To(BeEmpty())
+
+			minerList := &appsv1alpha1.MinerList{}
+			Expect(k8sClient.List(ctx, minerList, client.InNamespace("default"),
+				client.MatchingLabels{chainNameLabel: resourceName})).To(Succeed())
+			Expect(minerList.Items).To(BeEmpty())
+
+			By("Unpausing the Chain")
+			chain.Spec.Paused = false
+			Expect(k8sClient.Update(ctx, chain)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the resources are created")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			Expect(condition.Has(chain, condition.PausedCondition)).To(BeFalse())
+			Expect(chain.Status.ConfigMapRef).NotTo(BeNil())
+			Expect(chain.Status.MinerRef).NotTo(BeNil())
+
+			Expect(k8sClient.List(ctx, minerList, client.InNamespace("default"),
+				client.MatchingLabels{chainNameLabel: resourceName})).To(Succeed())
+			Expect(minerList.Items).To(HaveLen(1))
+		})
+	})
+
+	Context("When counting miners", func() {
+		const resourceName = "test-chain-counts"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			By("creating the custom resource for the Kind Chain")
+			resource := &appsv1alpha1.Chain{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: appsv1alpha1.ChainSpec{
+					MinerType: "small",
+					Image:     "nginx:alpine",
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &appsv1alpha1.Chain{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				resource.Finalizers = nil
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+
+			By("Cleaning up owned resources")
+			Expect(k8sClient.DeleteAllOf(ctx, &corev1.ConfigMap{}, client.InNamespace("default"),
+				client.MatchingLabels{chainNameLabel: resourceName})).To(Succeed())
+			Expect(k8sClient.DeleteAllOf(ctx, &appsv1alpha1.Miner{}, client.InNamespace("default"),
+				client.MatchingLabels{chainNameLabel: resourceName})).To(Succeed())
+		})
+
+		It("should report the number of miners and ready miners of the chain", func() {
+			By("Creating extra miners for the chain, one of them ready")
+			for i, ready := range []bool{true, false} {
+				miner := &appsv1alpha1.Miner{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      fmt.Sprintf("%s-extra-%d", resourceName, i),
+						Namespace: "default",
+						Labels:    map[string]string{chainNameLabel: resourceName},
+					},
+					Spec: appsv1alpha1.MinerSpec{
+						ChainName: resourceName,
+						MinerType: appsv1alpha1.MinerTypeSmall,
+					},
+				}
+				Expect(k8sClient.Create(ctx, miner)).To(Succeed())
+				miner.Status.Ready = ready
+				Expect(k8sClient.Status().Update(ctx, miner)).To(Succeed())
+			}
+
+			By("Reconciling the Chain")
+			controllerReconciler := &ChainReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the counts include the genesis miner")
+			chain := &appsv1alpha1.Chain{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			Expect(chain.Status.MinerCount).To(Equal(int32(3)))
+			Expect(chain.Status.ReadyMinerCount).To(Equal(int32(1)))
+		})
+
+		It("should not count terminating miners", func() {
+			By("Creating a ready miner for the chain that is being deleted")
+			miner := &appsv1alpha1.Miner{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       resourceName + "-terminating",
+					Namespace:  "default",
+					Labels:     map[string]string{chainNameLabel: resourceName},
+					Finalizers: []string{"test.onex.io/block-deletion"},
+				},
+				Spec: appsv1alpha1.MinerSpec{
+					ChainName: resourceName,
+					MinerType: appsv1alpha1.MinerTypeSmall,
+				},
+			}
+			Expect(k8sClient.Create(ctx, miner)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(miner), miner)).To(Succeed())
+				miner.Finalizers = nil
+				Expect(k8sClient.Update(ctx, miner)).To(Succeed())
+			})
+			miner.Status.Ready = true
+			Expect(k8sClient.Status().Update(ctx, miner)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, miner)).To(Succeed())
+
+			By("Reconciling the Chain")
+			controllerReconciler := &ChainReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking only the genesis miner is counted")
+			chain := &appsv1alpha1.Chain{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			Expect(chain.Status.MinerCount).To(Equal(int32(1)))
+			Expect(chain.Status.ReadyMinerCount).To(BeZero())
+		})
+	})
+
+	Context("When watching the genesis Miner", func() {
+		const resourceName = "test-chain-watch"
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			resource := &appsv1alpha1.Chain{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				resource.Finalizers = nil
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+
+			By("Cleaning up owned resources")
+			Expect(k8sClient.DeleteAllOf(ctx, &corev1.ConfigMap{}, client.InNamespace("default"),
+				client.MatchingLabels{chainNameLabel: resourceName})).To(Succeed())
+			Expect(k8sClient.DeleteAllOf(ctx, &appsv1alpha1.Miner{}, client.InNamespace("default"),
+				client.MatchingLabels{chainNameLabel: resourceName})).To(Succeed())
+		})
+
+		It("should reconcile the Chain when its genesis miner changes phase", func() {
+			By("Starting a manager running the Chain controller")
+			mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+				Scheme:  k8sClient.Scheme(),
+				Metrics: metricsserver.Options{BindAddress: "0"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect((&ChainReconciler{
+				Client: mgr.GetClient(),
+				Scheme: mgr.GetScheme(),
+			}).SetupWithManager(mgr, controller.Options{SkipNameValidation: ptr.To(true)})).To(Succeed())
+
+			mgrCtx, mgrCancel := context.WithCancel(ctx)
+			DeferCleanup(mgrCancel)
+			go func() {
+				defer GinkgoRecover()
+				Expect(mgr.Start(mgrCtx)).To(Succeed())
+			}()
+
+			By("Creating the Chain and waiting for its genesis miner")
+			chain := &appsv1alpha1.Chain{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: appsv1alpha1.ChainSpec{
+					MinerType: "small",
+					Image:     "nginx:alpine",
+				},
+			}
+			Expect(k8sClient.Create(ctx, chain)).To(Succeed())
+
+			miner := &appsv1alpha1.Miner{}
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+				g.Expect(chain.Status.MinerRef).NotTo(BeNil())
+				g.Expect(chain.Status.MinerCount).To(Equal(int32(1)))
+				g.Expect(chain.Status.ReadyMinerCount).To(BeZero())
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{
+					Name: chain.Status.MinerRef.Name, Namespace: "default",
+				}, miner)).To(Succeed())
+			}).Should(Succeed())
+
+			By("Moving the genesis miner to Running")
+			miner.Status.Phase = appsv1alpha1.MinerPhaseRunning
+			miner.Status.Ready = true
+			Expect(k8sClient.Status().Update(ctx, miner)).To(Succeed())
+
+			By("Checking the Chain status picks up the change")
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+				g.Expect(chain.Status.ReadyMinerCount).To(Equal(int32(1)))
+			}).Should(Succeed())
+		})
+	})
+
+	Context("When deleting a Chain", func() {
+		const resourceName = "test-chain-delete"
+		const blockingFinalizer = "test.onex.io/block"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			By("creating the custom resource for the Kind Chain")
+			resource := &appsv1alpha1.Chain{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: appsv1alpha1.ChainSpec{
+					MinerType: "small",
+					Image:     "nginx:alpine",
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		It("should delete the genesis Miner before the ConfigMap and the finalizer", func() {
+			controllerReconciler := &ChainReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			By("Reconciling the Chain to create its resources")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			chain := &appsv1alpha1.Chain{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			Expect(chain.Status.ConfigMapRef).NotTo(BeNil())
+			cmKey := types.NamespacedName{Name: chain.Status.ConfigMapRef.Name, Namespace: "default"}
+
+			By("Blocking deletion of the genesis Miner")
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			miner.Finalizers = append(miner.Finalizers, blockingFinalizer)
+			Expect(k8sClient.Update(ctx, miner)).To(Succeed())
+
+			By("Deleting the Chain")
+			Expect(k8sClient.Delete(ctx, chain)).To(Succeed())
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+			By("Checking the ConfigMap and the Chain are kept while the Miner exists")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			Expect(miner.DeletionTimestamp.IsZero()).To(BeFalse())
+			Expect(k8sClient.Get(ctx, cmKey, &corev1.ConfigMap{})).To(Succeed())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			Expect(chain.Finalizers).To(ContainElement(chainFinalizer))
+
+			By("Unblocking deletion of the genesis Miner")
+			miner.Finalizers = nil
+			Expect(k8sClient.Update(ctx, miner)).To(Succeed())
+
+			for i := 0; i < 2; i++ {
+				_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("Checking the ConfigMap and the Chain are deleted")
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, cmKey, &corev1.ConfigMap{}))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &appsv1alpha1.Chain{}))).To(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("GetGenesisMiner", func() {
+	ctx := context.Background()
+
+	newGenesisMiner := func(name string) *appsv1alpha1.Miner {
+		miner := &appsv1alpha1.Miner{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: appsv1alpha1.MinerSpec{
+				ChainName: "genesis-chain",
+				MinerType: appsv1alpha1.MinerTypeSmall,
+			},
+		}
+		Expect(k8sClient.Create(ctx, miner)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, miner))).To(Succeed())
+		})
+		return miner
+	}
+
+	chain := func() *appsv1alpha1.Chain {
+		return &appsv1alpha1.Chain{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "genesis-chain",
+				Namespace: "default",
+			},
+		}
+	}
+
+	It("should return the miner referenced by the chain status", func() {
+		newGenesisMiner("genesis-chain")
+		newGenesisMiner("renamed-genesis")
+		c := chain()
+		c.Status.MinerRef = &appsv1alpha1.LocalObjectReference{Name: "renamed-genesis"}
+
+		miner, err := GetGenesisMiner(ctx, k8sClient, c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(miner.Name).To(Equal("renamed-genesis"))
+	})
+
+	It("should fall back to the miner named after the chain", func() {
+		newGenesisMiner("genesis-chain")
+
+		miner, err := GetGenesisMiner(ctx, k8sClient, chain())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(miner.Name).To(Equal("genesis-chain"))
+	})
+
+	It("should report a missing genesis miner as not found", func() {
+		_, err := GetGenesisMiner(ctx, k8sClient, chain())
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+})
+
+var _ = DescribeTable("classifyChainError",
+	func(err error, reason string, terminal bool) {
+		gotReason, gotTerminal := classifyChainError(err)
+		Expect(gotTerminal).To(Equal(terminal))
+		Expect(gotReason).To(Equal(reason))
+	},
+	Entry("missing dependency", errors.NewNotFound(corev1.Resource("configmaps"), "config"), "", false),
+	Entry("conflict", errors.NewConflict(corev1.Resource("configmaps"), "config", fmt.Errorf("stale")), "", false),
+	Entry("unclassified error", fmt.Errorf("connection refused"), "", false),
+	Entry("invalid object", errors.NewInvalid(corev1.SchemeGroupVersion.WithKind("ConfigMap").GroupKind(), "config", nil),
+		string(condition.InvalidConfigurationReason), true),
+	Entry("bad request", errors.NewBadRequest("malformed"), string(condition.InvalidConfigurationReason), true),
+	Entry("wrapped invalid object", fmt.Errorf("failed to create: %w",
+		errors.NewInvalid(corev1.SchemeGroupVersion.WithKind("ConfigMap").GroupKind(), "config", nil)),
+		string(condition.InvalidConfigurationReason), true),
+)
+
+var _ = Describe("chainForMiner", func() {
+	It("should map a miner to the chain of its chain label", func() {
+		miner := &appsv1alpha1.Miner{ObjectMeta: metav1.ObjectMeta{
+			Name:      "standalone-miner",
+			Namespace: "default",
+			Labels:    map[string]string{chainNameLabel: "chain"},
+		}}
+		Expect(chainForMiner(ctx, miner)).To(ConsistOf(reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: "default", Name: "chain"},
+		}))
+	})
+
+	It("should ignore a miner without the chain label", func() {
+		miner := &appsv1alpha1.Miner{ObjectMeta: metav1.ObjectMeta{Name: "miner", Namespace: "default"}}
+		Expect(chainForMiner(ctx, miner)).To(BeEmpty())
+	})
 })
+
+// minerSetUpdateCountingClient is a client counting the updates of MinerSets.
+type minerSetUpdateCountingClient struct {
+	client.Client
+	updates int
+}
+
+func (c *minerSetUpdateCountingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if _, ok := obj.(*appsv1alpha1.MinerSet); ok {
+		c.updates++
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+// failingConfigMapClient is a client whose ConfigMap creations fail with err.
+type failingConfigMapClient struct {
+	client.Client
+	err error
+}
+
+func (c *failingConfigMapClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if _, ok := obj.(*corev1.ConfigMap); ok {
+		return c.err
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
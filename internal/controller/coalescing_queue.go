@@ -0,0 +1,54 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultChainCoalesceWindow is how long the Chain controller holds back a request
+// triggered by a watch event, so that a burst of edits results in a single reconcile.
+const DefaultChainCoalesceWindow = time.Second
+
+// coalescingQueue delays every request added by an event handler by window. The delaying
+// queue keeps a single entry per request while it waits, so all events received within
+// the window are served by one reconcile. Requeues requested by the reconciler go through
+// the rate limiter as usual.
+type coalescingQueue struct {
+	workqueue.TypedRateLimitingInterface[reconcile.Request]
+	window time.Duration
+}
+
+// Add implements workqueue.TypedInterface.
+func (q *coalescingQueue) Add(item reconcile.Request) {
+	q.AddAfter(item, q.window)
+}
+
+// newCoalescingQueue returns a controller.Options.NewQueue function building a rate
+// limited queue that coalesces requests received within window.
+func newCoalescingQueue(window time.Duration) func(string, workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+	return func(controllerName string, rateLimiter workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+		return &coalescingQueue{
+			TypedRateLimitingInterface: workqueue.NewTypedRateLimitingQueueWithConfig(rateLimiter,
+				workqueue.TypedRateLimitingQueueConfig[reconcile.Request]{Name: controllerName}),
+			window: window,
+		}
+	}
+}
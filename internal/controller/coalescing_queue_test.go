@@ -0,0 +1,139 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1alpha1 "github.com/ashwinyue/minerx/api/v1alpha1"
+)
+
+var _ = Describe("Chain request coalescing", func() {
+	const resourceName = "test-chain-coalesce"
+
+	ctx := context.Background()
+
+	typeNamespacedName := types.NamespacedName{
+		Name:      resourceName,
+		Namespace: "default",
+	}
+
+	BeforeEach(func() {
+		resource := &appsv1alpha1.Chain{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resourceName,
+				Namespace: "default",
+			},
+			Spec: appsv1alpha1.ChainSpec{
+				MinerType: "small",
+				Image:     "nginx:alpine",
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		resource := &appsv1alpha1.Chain{}
+		if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+			resource.Finalizers = nil
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		}
+
+		Expect(k8sClient.DeleteAllOf(ctx, &corev1.ConfigMap{}, client.InNamespace("default"),
+			client.MatchingLabels{chainNameLabel: resourceName})).To(Succeed())
+		Expect(k8sClient.DeleteAllOf(ctx, &appsv1alpha1.Miner{}, client.InNamespace("default"),
+			client.MatchingLabels{chainNameLabel: resourceName})).To(Succeed())
+	})
+
+	It("should reconcile a burst of edits once", func() {
+		queue := newCoalescingQueue(200*time.Millisecond)("chain",
+			workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+		DeferCleanup(queue.ShutDown)
+
+		By("Editing the Chain rapidly, enqueueing a request for every edit")
+		const edits = 10
+		for i := range edits {
+			chain := &appsv1alpha1.Chain{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, chain)).To(Succeed())
+			chain.Spec.ExtraConfig = map[string]string{"edit": fmt.Sprint(i)}
+			Expect(k8sClient.Update(ctx, chain)).To(Succeed())
+			queue.Add(reconcile.Request{NamespacedName: typeNamespacedName})
+		}
+		Expect(queue.Len()).To(BeZero())
+
+		By("Processing the queue")
+		countingClient := &configMapWriteCountingClient{Client: k8sClient}
+		controllerReconciler := &ChainReconciler{
+			Client: countingClient,
+			Scheme: k8sClient.Scheme(),
+		}
+
+		reconciles := 0
+		Eventually(queue.Len).Should(Equal(1))
+		for queue.Len() > 0 {
+			req, _ := queue.Get()
+			_, err := controllerReconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			queue.Forget(req)
+			queue.Done(req)
+			reconciles++
+		}
+		Consistently(queue.Len, "500ms").Should(BeZero())
+
+		Expect(reconciles).To(Equal(1))
+		Expect(countingClient.configMapWrites).To(BeNumerically("<=", 1))
+	})
+})
+
+// configMapWriteCountingClient is a client that counts writes to ConfigMaps.
+type configMapWriteCountingClient struct {
+	client.Client
+	configMapWrites int
+}
+
+func (c *configMapWriteCountingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if _, ok := obj.(*corev1.ConfigMap); ok {
+		c.configMapWrites++
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *configMapWriteCountingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if _, ok := obj.(*corev1.ConfigMap); ok {
+		c.configMapWrites++
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *configMapWriteCountingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if _, ok := obj.(*corev1.ConfigMap); ok {
+		c.configMapWrites++
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// reconcilePanicsTotal counts the panics recovered during reconciliation.
+	reconcilePanicsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "minerx_reconcile_panics_total",
+			Help: "Total number of panics recovered during reconciliation per controller.",
+		},
+		[]string{"controller"},
+	)
+
+	// minerTimeToReadySeconds observes how long miners take from creation to their first Running phase,
+	// recorded in the FirstRunningTime of their status.
+	minerTimeToReadySeconds = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "minerx_miner_time_to_ready_seconds",
+			Help:    "Time from Miner creation until it first reaches the Running phase.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+		},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(reconcilePanicsTotal, minerTimeToReadySeconds)
+}
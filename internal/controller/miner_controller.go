@@ -17,45 +17,123 @@ limitations under the License.
 package controller
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	appsv1alpha1 "github.com/ashwinyue/minerx/api/v1alpha1"
 	"github.com/ashwinyue/minerx/pkg/condition"
 )
 
 const (
-	minerFinalizer    = "miner.onex.io/finalizer"
-	defaultPodTimeout = 10 * time.Second
+	minerFinalizer     = "miner.onex.io/finalizer"
+	minerNameLabel     = "miner.onex.io/name"
+	minerContainerName = "miner"
+	defaultPodTimeout  = appsv1alpha1.DefaultMinerPodDeletionTimeout
+
+	// maxPodNameLength is the longest pod name kept as is, which is also the longest
+	// value of the miner name label on the pod.
+	maxPodNameLength = validation.DNS1123LabelMaxLength
+
+	// chainConfigVolumeName names both the chain ConfigMap volume and the init container
+	// that mounts it at chainConfigMountPath.
+	chainConfigVolumeName = "chain-config"
+	chainConfigMountPath  = "/etc/chain"
+
+	// defaultRunAsUser is the user the miner containers run as unless the miner sets a
+	// security context: the "nobody" user of most base images. RunAsNonRoot alone would
+	// keep images whose default user is root, such as the built-in miner images, from starting.
+	defaultRunAsUser int64 = 65534
+
+	podCreationBaseBackoff = 5 * time.Second
+	podCreationMaxBackoff  = 5 * time.Minute
+
+	// imagePullFailureThreshold is how long a pod may fail to pull its image
+	// before the miner is marked as Failed.
+	imagePullFailureThreshold = 5 * time.Minute
+
+	// unschedulableFailureThreshold is how long a pod may remain unschedulable
+	// before the miner is marked as Failed.
+	unschedulableFailureThreshold = 5 * time.Minute
+
+	// configChecksumAnnotation records the hash of the chain ConfigMap the pod was created
+	// with. The pod is recreated when the ConfigMap no longer matches it.
+	configChecksumAnnotation = "miner.onex.io/config-checksum"
+
+	// commandAnnotation overrides the placeholder command of the miner container. The value
+	// is split into arguments like a shell would, without expansions, e.g. "sh -c 'sleep 60'".
+	commandAnnotation = "miner.onex.io/command"
+
+	// drainRequestedAnnotation is set on the pod of a deleted miner to ask its workload to
+	// drain, for example to leave the peer network. The workload sets drainedAnnotation on
+	// the pod once it is done, after which the pod is deleted.
+	drainRequestedAnnotation = "miner.onex.io/drain-requested"
+	drainedAnnotation        = "miner.onex.io/drained"
+
+	// podRestartRequeueInterval is how long to wait before recreating a pod that was
+	// deleted to pick up a new chain configuration.
+	podRestartRequeueInterval = 2 * time.Second
+
+	// podConflictRequeueInterval is how often to check whether a pod named after the miner
+	// but controlled by another object is gone.
+	podConflictRequeueInterval = 30 * time.Second
+
+	// minerResyncInterval is how often a miner is reconciled when its pod asks for no
+	// earlier requeue, so that the pod failure thresholds are noticed.
+	minerResyncInterval = 10 * time.Second
 )
 
 // MinerReconciler reconciles a Miner object
 type MinerReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// DisableFinalizers stops the controller from adding its finalizer, see --disable-finalizers.
+	DisableFinalizers bool
+
+	// LogReader reads the logs captured into the failure message of miners that set
+	// FailureLogTailLines. No logs are captured when nil.
+	LogReader PodLogReader
 }
 
 // +kubebuilder:rbac:groups=apps.onex.io,resources=miners,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.onex.io,resources=miners/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps.onex.io,resources=miners/finalizers,verbs=update
+// +kubebuilder:rbac:groups=apps.onex.io,resources=chains,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=pods/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=pods/log,verbs=get
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
-func (r *MinerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *MinerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	defer recoverReconcilePanic(ctx, "miner", req, &result, &err)
+
 	log := log.FromContext(ctx)
 
 	miner := &appsv1alpha1.Miner{}
@@ -80,21 +158,51 @@ func (r *MinerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 func (r *MinerReconciler) reconcileDelete(ctx context.Context, miner *appsv1alpha1.Miner) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 
-	condition.SetFalse(miner, condition.MinerPodHealthyCondition, condition.DeletingReason, "Deleting pod")
-
-	if err := r.Status().Update(ctx, miner); err != nil {
-		log.Error(err, "Failed to update Miner status")
-		return ctrl.Result{}, err
+	// Find the pod to delete, unless it is managed externally
+	podName := minerPodName(miner)
+	var pod *corev1.Pod
+	if !managesPod(miner) {
+		log.Info("Pod is managed externally, skipping deletion")
+	} else {
+		pod = &corev1.Pod{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: miner.Namespace, Name: podName}, pod); err != nil {
+			if !errors.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+			pod = nil
+		}
 	}
 
-	// Delete pod
-	podName := miner.Name
-	pod := &corev1.Pod{}
-	if err := r.Get(ctx, client.ObjectKey{Namespace: miner.Namespace, Name: podName}, pod); err != nil {
-		if !errors.IsNotFound(err) {
+	var drainRequeueAfter time.Duration
+	if pod != nil {
+		var err error
+		if drainRequeueAfter, err = r.drainPod(ctx, miner, pod); err != nil {
+			log.Error(err, "Failed to request pod drain")
 			return ctrl.Result{}, err
 		}
+	}
+
+	observed := miner.Status.DeepCopy()
+	if drainRequeueAfter > 0 {
+		miner.Status.Phase = appsv1alpha1.MinerPhaseDraining
+		condition.SetFalse(miner, condition.MinerPodHealthyCondition, condition.DrainingReason, "Waiting for the pod to drain")
 	} else {
+		condition.SetFalse(miner, condition.MinerPodHealthyCondition, condition.DeletingReason, "Deleting pod")
+	}
+	miner.Status.Ready = false
+
+	if !equality.Semantic.DeepEqual(observed, &miner.Status) {
+		if err := updateStatusWithRetry(ctx, r.Client, miner); err != nil {
+			log.Error(err, "Failed to update Miner status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if drainRequeueAfter > 0 {
+		return ctrl.Result{RequeueAfter: drainRequeueAfter}, nil
+	}
+
+	if pod != nil {
 		// Determine deletion timeout
 		timeout := defaultPodTimeout
 		if miner.Spec.PodDeletionTimeout != nil {
@@ -129,10 +237,44 @@ func (r *MinerReconciler) reconcileDelete(ctx context.Context, miner *appsv1alph
 	return ctrl.Result{}, nil
 }
 
+// drainPod asks the pod of a deleted miner to drain when the miner has a drain timeout.
+// It returns how long to wait before checking the drain again, or zero once the pod can
+// be deleted: the pod is annotated as drained, or the drain timeout since the miner
+// deletion has passed.
+func (r *MinerReconciler) drainPod(ctx context.Context, miner *appsv1alpha1.Miner, pod *corev1.Pod) (time.Duration, error) {
+	log := log.FromContext(ctx)
+
+	if miner.Spec.DrainTimeoutSeconds == nil || pod.Annotations[drainedAnnotation] == "true" {
+		return 0, nil
+	}
+
+	timeout := time.Duration(*miner.Spec.DrainTimeoutSeconds) * time.Second
+	remaining := time.Until(miner.DeletionTimestamp.Add(timeout))
+	if remaining <= 0 {
+		log.Info("Timed out waiting for the pod to drain", "pod", pod.Name, "timeout", timeout)
+		return 0, nil
+	}
+
+	if pod.Annotations[drainRequestedAnnotation] != "true" {
+		patch := client.MergeFrom(pod.DeepCopy())
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[drainRequestedAnnotation] = "true"
+		if err := r.Patch(ctx, pod, patch); err != nil {
+			return 0, err
+		}
+		log.Info("Requested pod drain", "pod", pod.Name)
+	}
+
+	// The owned pod is watched, so annotating it as drained triggers the next reconcile
+	return remaining, nil
+}
+
 func (r *MinerReconciler) reconcile(ctx context.Context, miner *appsv1alpha1.Miner) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 
-	if !controllerutil.ContainsFinalizer(miner, minerFinalizer) {
+	if !r.DisableFinalizers && !controllerutil.ContainsFinalizer(miner, minerFinalizer) {
 		controllerutil.AddFinalizer(miner, minerFinalizer)
 		if err := r.Update(ctx, miner); err != nil {
 			log.Error(err, "Failed to add finalizer to Miner")
@@ -140,9 +282,18 @@ func (r *MinerReconciler) reconcile(ctx context.Context, miner *appsv1alpha1.Min
 		}
 	}
 
-	// Create or update pod
-	if err := r.reconcilePod(ctx, miner); err != nil {
-		return ctrl.Result{}, err
+	observed := miner.Status.DeepCopy()
+
+	// Create or update pod, unless the miner spec is invalid
+	var result ctrl.Result
+	if err := validateMinerSpec(miner); err != nil {
+		log.Info("Invalid miner spec, skipping pod creation", "reason", err.Error())
+		condition.SetFalse(miner, condition.InfrastructureReadyCondition, condition.InvalidConfigurationReason, err.Error())
+	} else {
+		result, err = r.reconcilePod(ctx, miner)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
 	}
 
 	// Update phase
@@ -150,57 +301,290 @@ func (r *MinerReconciler) reconcile(ctx context.Context, miner *appsv1alpha1.Min
 		miner.Status.Phase = appsv1alpha1.MinerPhaseProvisioning
 	}
 
-	// Sync pod status
-	if err := r.syncPodStatus(ctx, miner); err != nil {
+	// A miner whose chain was deleted keeps running, but cannot bootstrap against it anymore
+	chain, err := r.getChain(ctx, miner)
+	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	// Update status
-	miner.Status.LastUpdated = &metav1.Time{Time: time.Now()}
-	if err := r.Status().Update(ctx, miner); err != nil {
-		log.Error(err, "Failed to update Miner status")
+	// Sync pod status
+	if err := r.syncPodStatus(ctx, miner, chain); err != nil {
 		return ctrl.Result{}, err
 	}
+	if chain == nil {
+		condition.MarkFalsef(miner, condition.BootstrapReadyCondition, condition.ChainNotFoundReason,
+			"Chain %q does not exist", miner.Spec.ChainName)
+	}
+
+	miner.Status.Ready = isMinerReady(miner)
+
+	// Update status, skipping the write when nothing changed
+	if !equality.Semantic.DeepEqual(observed, &miner.Status) {
+		miner.Status.LastUpdated = &metav1.Time{Time: time.Now()}
+		if err := updateStatusWithRetry(ctx, r.Client, miner); err != nil {
+			log.Error(err, "Failed to update Miner status")
+			return ctrl.Result{}, err
+		}
+	}
 
 	log.Info("Miner reconciled successfully")
-	return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	return resyncResult(result, minerResyncInterval), nil
 }
 
-func (r *MinerReconciler) reconcilePod(ctx context.Context, miner *appsv1alpha1.Miner) error {
+// reconcilePod creates the miner pod if it does not exist yet. A pod that already exists,
+// for example because it was created by a concurrent reconcile, is treated as success
+// unless another object controls it.
+// Consecutive creation failures are recorded in the miner status and retried with a
+// capped exponential backoff. An existing pod is deleted, and recreated on a later
+// reconcile, once its restart policy or config checksum no longer match the miner.
+func (r *MinerReconciler) reconcilePod(ctx context.Context, miner *appsv1alpha1.Miner) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 
+	if !managesPod(miner) {
+		return ctrl.Result{}, nil
+	}
+
+	if name := minerPodName(miner); name != miner.Name {
+		condition.Set(miner, metav1.Condition{
+			Type:               string(condition.PodNameTruncatedCondition),
+			Status:             metav1.ConditionTrue,
+			Reason:             string(condition.PodNameTruncatedReason),
+			Message:            fmt.Sprintf("Miner name is longer than %d characters, its pod is named %q", maxPodNameLength, name),
+			LastTransitionTime: metav1.Now(),
+		})
+	} else {
+		condition.Delete(miner, condition.PodNameTruncatedCondition)
+	}
+
 	pod := &corev1.Pod{}
-	if err := r.Get(ctx, client.ObjectKey{Namespace: miner.Namespace, Name: miner.Name}, pod); err != nil {
+	if err := r.Get(ctx, client.ObjectKey{Namespace: miner.Namespace, Name: minerPodName(miner)}, pod); err != nil {
 		if !errors.IsNotFound(err) {
-			return err
+			return ctrl.Result{}, err
 		}
 
 		// Pod doesn't exist, create it
-		desiredPod := r.createPodSpec(miner)
-		if err := r.Create(ctx, desiredPod); err != nil {
-			log.Error(err, "Failed to create pod")
-			condition.SetFalse(miner, condition.InfrastructureReadyCondition, condition.FailedReason, fmt.Sprintf("Failed to create pod: %v", err))
-			return err
+		chain, err := r.getChain(ctx, miner)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		data, err := r.podAnnotationData(ctx, miner, chain)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		desiredPod := r.createPodSpec(miner, data)
+		if chain != nil {
+			desiredPod.Spec.ImagePullSecrets = mergeImagePullSecrets(desiredPod.Spec.ImagePullSecrets, chain.Spec.ImagePullSecrets)
+		}
+		if miner.Spec.ChainConfigInitContainer {
+			if chain == nil || chain.Status.ConfigMapRef == nil {
+				log.Info("Waiting for Chain ConfigMap to be created", "chain", miner.Spec.ChainName)
+				condition.MarkFalsef(miner, condition.InfrastructureReadyCondition, condition.WaitingForChainReason,
+					"Waiting for Chain %q to create its ConfigMap", miner.Spec.ChainName)
+				return ctrl.Result{RequeueAfter: chainReadyRequeueInterval}, nil
+			}
+			// The config hash is only empty while the referenced ConfigMap does not exist
+			if data.ConfigHash == "" {
+				log.Info("Waiting for Chain ConfigMap to exist", "configMap", chain.Status.ConfigMapRef.Name)
+				condition.MarkFalsef(miner, condition.BootstrapReadyCondition, condition.WaitingForConfigMapReason,
+					"Waiting for ConfigMap %q of Chain %q", chain.Status.ConfigMapRef.Name, miner.Spec.ChainName)
+				return ctrl.Result{RequeueAfter: chainReadyRequeueInterval}, nil
+			}
+			if cond := condition.Get(miner, condition.BootstrapReadyCondition); cond != nil && cond.Reason == string(condition.WaitingForConfigMapReason) {
+				condition.Delete(miner, condition.BootstrapReadyCondition)
+			}
+			addChainConfigInitContainer(desiredPod, chain.Status.ConfigMapRef.Name)
 		}
+		if err := r.Create(ctx, desiredPod); err != nil {
+			if errors.IsAlreadyExists(err) {
+				// The pod was created since we last looked, adopt the existing one unless another
+				// object controls it
+				if err := r.Get(ctx, client.ObjectKeyFromObject(desiredPod), desiredPod); err != nil {
+					return ctrl.Result{}, err
+				}
+				if owner := metav1.GetControllerOf(desiredPod); owner != nil && owner.UID != miner.UID {
+					log.Info("Pod already exists and is controlled by another object",
+						"pod", desiredPod.Name, "controllerKind", owner.Kind, "controller", owner.Name)
+					condition.MarkFalsef(miner, condition.InfrastructureReadyCondition, condition.PodConflictReason,
+						"Pod %q is controlled by %s %q", desiredPod.Name, owner.Kind, owner.Name)
+					return ctrl.Result{RequeueAfter: podConflictRequeueInterval}, nil
+				}
+				log.Info("Pod already exists", "pod", desiredPod.Name)
+				miner.Status.PodRef = podReference(desiredPod)
+				condition.SetTrue(miner, condition.InfrastructureReadyCondition)
+				miner.Status.PodCreationFailures = 0
+				return ctrl.Result{}, nil
+			}
 
-		miner.Status.PodRef = &corev1.ObjectReference{
-			Kind:       "Pod",
-			Namespace:  desiredPod.Namespace,
-			Name:       desiredPod.Name,
-			UID:        desiredPod.UID,
-			APIVersion: "v1",
+			miner.Status.PodCreationFailures++
+			backoff := podCreationBackoff(miner.Status.PodCreationFailures)
+			log.Error(err, "Failed to create pod", "failures", miner.Status.PodCreationFailures, "backoff", backoff)
+			condition.MarkFalsef(miner, condition.InfrastructureReadyCondition, condition.FailedReason,
+				"Failed to create pod (%d consecutive failures, retrying in %s): %v", miner.Status.PodCreationFailures, backoff, err)
+			return ctrl.Result{RequeueAfter: backoff}, nil
 		}
 
+		miner.Status.PodRef = podReference(desiredPod)
+
 		log.Info("Created pod", "pod", desiredPod.Name)
 		condition.SetTrue(miner, condition.InfrastructureReadyCondition)
+	} else if pod.DeletionTimestamp.IsZero() {
+		// Recreate the pod when it no longer matches settings that cannot be changed in place
+		chain, err := r.getChain(ctx, miner)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		data, err := r.podAnnotationData(ctx, miner, chain)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if reason, message := podRecreateReason(miner, pod, data.ConfigHash); reason != "" {
+			log.Info("Recreating pod", "pod", pod.Name, "reason", message)
+			if err := r.Delete(ctx, pod, client.Preconditions{UID: &pod.UID}); err != nil && !errors.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+			condition.SetFalse(miner, condition.InfrastructureReadyCondition, reason, message)
+			return ctrl.Result{RequeueAfter: podRestartRequeueInterval}, nil
+		}
+	}
+
+	miner.Status.PodCreationFailures = 0
+	return ctrl.Result{}, nil
+}
+
+// podRecreateReason returns why the pod has to be recreated to match the miner, or an
+// empty reason if it is up to date. The restart policy of a pod is immutable, and the chain
+// configuration is only read when the pod starts.
+func podRecreateReason(miner *appsv1alpha1.Miner, pod *corev1.Pod, checksum string) (condition.ConditionReason, string) {
+	if policy := restartPolicy(miner); pod.Spec.RestartPolicy != policy {
+		return condition.RestartPolicyChangedReason,
+			fmt.Sprintf("Recreating pod to change its restart policy from %s to %s", pod.Spec.RestartPolicy, policy)
+	}
+	if configChanged(pod, checksum) {
+		return condition.ConfigChangedReason, "Recreating pod to pick up the new chain configuration"
+	}
+	return "", ""
+}
+
+// restartPolicy returns the restart policy of the miner pod, defaulting to Always.
+func restartPolicy(miner *appsv1alpha1.Miner) corev1.RestartPolicy {
+	if miner.Spec.RestartPolicy == "" {
+		return corev1.RestartPolicyAlways
+	}
+	return miner.Spec.RestartPolicy
+}
+
+// configChanged returns true if the pod was created with a chain configuration other than
+// the one with the given checksum. Pods without a checksum, such as pods created before the
+// chain ConfigMap existed or by an older controller, are left alone.
+func configChanged(pod *corev1.Pod, checksum string) bool {
+	current, ok := pod.Annotations[configChecksumAnnotation]
+	return ok && checksum != "" && current != checksum
+}
+
+// podReference returns an object reference to the given pod.
+func podReference(pod *corev1.Pod) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		Kind:       "Pod",
+		Namespace:  pod.Namespace,
+		Name:       pod.Name,
+		UID:        pod.UID,
+		APIVersion: "v1",
+	}
+}
+
+// podCreationBackoff returns the delay before retrying pod creation after the given
+// number of consecutive failures, doubling from podCreationBaseBackoff up to podCreationMaxBackoff.
+func podCreationBackoff(failures int32) time.Duration {
+	backoff := podCreationBaseBackoff
+	for i := int32(1); i < failures; i++ {
+		backoff *= 2
+		if backoff >= podCreationMaxBackoff {
+			return podCreationMaxBackoff
+		}
+	}
+	return backoff
+}
+
+// isMinerReady returns true if the miner is Running and its pod is healthy.
+func isMinerReady(miner *appsv1alpha1.Miner) bool {
+	return miner.Status.Phase == appsv1alpha1.MinerPhaseRunning &&
+		condition.IsTrue(miner, condition.MinerPodHealthyCondition)
+}
+
+// validateMinerSpec returns an error describing why no pod can be built for the miner.
+func validateMinerSpec(miner *appsv1alpha1.Miner) error {
+	if !isKnownMinerType(miner.Spec.MinerType) {
+		return fmt.Errorf("unknown miner type %q, must be one of %q, %q or %q", miner.Spec.MinerType,
+			appsv1alpha1.MinerTypeSmall, appsv1alpha1.MinerTypeMedium, appsv1alpha1.MinerTypeLarge)
+	}
+
+	if errs := validation.IsDNS1123Label(containerName(miner)); len(errs) > 0 {
+		return fmt.Errorf("invalid container name %q: %s", containerName(miner), strings.Join(errs, ", "))
+	}
+
+	if name := miner.Spec.PriorityClassName; name != "" {
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			return fmt.Errorf("invalid priority class name %q: %s", name, strings.Join(errs, ", "))
+		}
+	}
+
+	if name := ptr.Deref(miner.Spec.RuntimeClassName, ""); name != "" {
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			return fmt.Errorf("invalid runtime class name %q: %s", name, strings.Join(errs, ", "))
+		}
+	}
+
+	if miner.Spec.DNSPolicy == corev1.DNSNone && miner.Spec.DNSConfig == nil {
+		return fmt.Errorf("dns config is required when the dns policy is %q", corev1.DNSNone)
+	}
+
+	names := MinerPodContainerNames(&miner.Spec)
+	for _, sidecar := range miner.Spec.Sidecars {
+		if names.Has(sidecar.Name) {
+			return fmt.Errorf("sidecar container name %q is already used in the pod", sidecar.Name)
+		}
+		names.Insert(sidecar.Name)
+	}
+	for _, initContainer := range miner.Spec.InitContainers {
+		if names.Has(initContainer.Name) {
+			return fmt.Errorf("init container name %q is already used in the pod", initContainer.Name)
+		}
+		names.Insert(initContainer.Name)
 	}
 
+	if value, ok := miner.Annotations[commandAnnotation]; ok {
+		if _, err := splitCommand(value); err != nil {
+			return fmt.Errorf("invalid command in annotation %q: %w", commandAnnotation, err)
+		}
+	}
+
+	// Rendering with empty data catches both syntax errors and unknown fields
+	if _, err := renderPodAnnotations(miner.Annotations, podAnnotationData{}); err != nil {
+		return err
+	}
 	return nil
 }
 
-func (r *MinerReconciler) createPodSpec(miner *appsv1alpha1.Miner) *corev1.Pod {
+// isKnownMinerType returns true if the miner type maps to a pod profile in createPodSpec.
+func isKnownMinerType(minerType appsv1alpha1.MinerType) bool {
+	switch minerType {
+	case appsv1alpha1.MinerTypeSmall, appsv1alpha1.MinerTypeMedium, appsv1alpha1.MinerTypeLarge:
+		return true
+	}
+	return false
+}
+
+// createPodSpec returns the pod of the miner. Templated miner annotations are rendered with
+// the given data; validateMinerSpec has already rejected templates that fail to render.
+func (r *MinerReconciler) createPodSpec(miner *appsv1alpha1.Miner, data podAnnotationData) *corev1.Pod {
 	image := "busybox"
 	command := []string{"sh", "-c", "sleep 3600"}
+	if value, ok := miner.Annotations[commandAnnotation]; ok {
+		// validateMinerSpec has already rejected commands that do not parse
+		if args, err := splitCommand(value); err == nil {
+			command = args
+		}
+	}
 
 	if miner.Spec.MinerType == "small" {
 		image = "nginx:alpine"
@@ -210,26 +594,33 @@ func (r *MinerReconciler) createPodSpec(miner *appsv1alpha1.Miner) *corev1.Pod {
 		image = "redis:alpine"
 	}
 
-	labels := map[string]string{
-		"app":                "miner",
-		"miner.onex.io/name": miner.Name,
-		"chain.onex.io/name": miner.Spec.ChainName,
+	// Controller labels are set last so that user labels cannot break the pod selectors
+	labels := make(map[string]string, len(miner.Labels)+3)
+	for k, v := range miner.Labels {
+		labels[k] = v
 	}
+	labels["app"] = "miner"
+	labels[minerNameLabel] = minerPodName(miner)
+	labels[chainNameLabel] = miner.Spec.ChainName
 
-	if miner.Labels != nil {
-		for k, v := range miner.Labels {
-			labels[k] = v
-		}
+	annotations := map[string]string{
+		minerNameLabel: miner.Name,
+	}
+
+	rendered, _ := renderPodAnnotations(miner.Annotations, data)
+	for k, v := range rendered {
+		annotations[k] = v
+	}
+	if data.ConfigHash != "" {
+		annotations[configChecksumAnnotation] = data.ConfigHash
 	}
 
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      miner.Name,
-			Namespace: miner.Namespace,
-			Labels:    labels,
-			Annotations: map[string]string{
-				"miner.onex.io/name": miner.Name,
-			},
+			Name:        minerPodName(miner),
+			Namespace:   miner.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
 			OwnerReferences: []metav1.OwnerReference{
 				{
 					APIVersion:         appsv1alpha1.GroupVersion.String(),
@@ -244,43 +635,217 @@ func (r *MinerReconciler) createPodSpec(miner *appsv1alpha1.Miner) *corev1.Pod {
 		Spec: corev1.PodSpec{
 			Containers: []corev1.Container{
 				{
-					Name:    "miner",
-					Image:   image,
-					Command: command,
+					Name:            containerName(miner),
+					Image:           image,
+					Command:         command,
+					Resources:       *miner.Spec.Resources.DeepCopy(),
+					SecurityContext: miner.Spec.SecurityContext.DeepCopy(),
 				},
 			},
-			RestartPolicy: miner.Spec.RestartPolicy,
+			RestartPolicy:         restartPolicy(miner),
+			ActiveDeadlineSeconds: miner.Spec.ActiveDeadlineSeconds,
+			SecurityContext:       miner.Spec.PodSecurityContext.DeepCopy(),
+			ImagePullSecrets:      mergeImagePullSecrets(nil, miner.Spec.ImagePullSecrets),
+			PriorityClassName:     miner.Spec.PriorityClassName,
+			RuntimeClassName:      miner.Spec.RuntimeClassName,
+			DNSPolicy:             miner.Spec.DNSPolicy,
+			DNSConfig:             miner.Spec.DNSConfig.DeepCopy(),
 		},
 	}
 
-	if pod.Spec.RestartPolicy == "" {
-		pod.Spec.RestartPolicy = corev1.RestartPolicyAlways
+	if pod.Spec.SecurityContext == nil {
+		pod.Spec.SecurityContext = defaultPodSecurityContext()
+	}
+
+	if pod.Spec.Containers[0].SecurityContext == nil {
+		pod.Spec.Containers[0].SecurityContext = defaultContainerSecurityContext()
+	}
+
+	for i := range miner.Spec.InitContainers {
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, *miner.Spec.InitContainers[i].DeepCopy())
+	}
+
+	for i := range miner.Spec.HostAliases {
+		pod.Spec.HostAliases = append(pod.Spec.HostAliases, *miner.Spec.HostAliases[i].DeepCopy())
+	}
+
+	// Sidecars run alongside the miner container, after it in the pod spec
+	for i := range miner.Spec.Sidecars {
+		pod.Spec.Containers = append(pod.Spec.Containers, *miner.Spec.Sidecars[i].DeepCopy())
 	}
 
 	return pod
 }
 
-func (r *MinerReconciler) syncPodStatus(ctx context.Context, miner *appsv1alpha1.Miner) error {
+// getChain returns the Chain the miner belongs to, or nil if the chain does not exist.
+func (r *MinerReconciler) getChain(ctx context.Context, miner *appsv1alpha1.Miner) (*appsv1alpha1.Chain, error) {
+	chain := &appsv1alpha1.Chain{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: miner.Namespace, Name: miner.Spec.ChainName}, chain); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return chain, nil
+}
+
+// podAnnotationData returns the data for templated pod annotations. The config hash is
+// only known once the chain has created its ConfigMap.
+func (r *MinerReconciler) podAnnotationData(ctx context.Context, miner *appsv1alpha1.Miner, chain *appsv1alpha1.Chain) (podAnnotationData, error) {
+	data := podAnnotationData{
+		MinerName: miner.Name,
+		ChainName: miner.Spec.ChainName,
+	}
+	if chain == nil || chain.Status.ConfigMapRef == nil {
+		return data, nil
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: chain.Namespace, Name: chain.Status.ConfigMapRef.Name}, cm); err != nil {
+		if errors.IsNotFound(err) {
+			return data, nil
+		}
+		return data, err
+	}
+	hash, err := configHash(cm.Data)
+	if err != nil {
+		return data, err
+	}
+	data.ConfigHash = hash
+	return data, nil
+}
+
+// MinerPodContainerNames returns the names of the containers the controller adds to the
+// pod of a miner with the given spec: the miner container and the chain config init
+// container. Sidecars and init containers must not reuse them.
+func MinerPodContainerNames(spec *appsv1alpha1.MinerSpec) sets.Set[string] {
+	return sets.New(cmp.Or(spec.ContainerName, minerContainerName), chainConfigVolumeName)
+}
+
+// addChainConfigInitContainer mounts the chain ConfigMap into the pod and prepends an
+// init container that checks the configuration is present before any other container starts.
+func addChainConfigInitContainer(pod *corev1.Pod, configMapName string) {
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: chainConfigVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: configMapName},
+			},
+		},
+	})
+
+	initContainer := corev1.Container{
+		Name:    chainConfigVolumeName,
+		Image:   "busybox",
+		Command: []string{"sh", "-c", fmt.Sprintf("test -s %s/chainName", chainConfigMountPath)},
+		VolumeMounts: []corev1.VolumeMount{{
+			Name:      chainConfigVolumeName,
+			MountPath: chainConfigMountPath,
+			ReadOnly:  true,
+		}},
+		SecurityContext: defaultContainerSecurityContext(),
+	}
+	pod.Spec.InitContainers = append([]corev1.Container{initContainer}, pod.Spec.InitContainers...)
+}
+
+// mergeImagePullSecrets appends the secrets of extra that are not yet in secrets.
+func mergeImagePullSecrets(secrets, extra []corev1.LocalObjectReference) []corev1.LocalObjectReference {
+	for _, secret := range extra {
+		if !slices.Contains(secrets, secret) {
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets
+}
+
+// defaultPodSecurityContext returns a pod security context complying with the
+// "restricted" Pod Security Standard, running as defaultRunAsUser.
+func defaultPodSecurityContext() *corev1.PodSecurityContext {
+	return &corev1.PodSecurityContext{
+		RunAsNonRoot: ptr.To(true),
+		RunAsUser:    ptr.To(defaultRunAsUser),
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+}
+
+// defaultContainerSecurityContext returns a container security context complying with
+// the "restricted" Pod Security Standard, running as defaultRunAsUser.
+func defaultContainerSecurityContext() *corev1.SecurityContext {
+	return &corev1.SecurityContext{
+		RunAsNonRoot:             ptr.To(true),
+		RunAsUser:                ptr.To(defaultRunAsUser),
+		AllowPrivilegeEscalation: ptr.To(false),
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+}
+
+// syncPodStatus updates the miner status from its pod. BootstrapReady is only set when the
+// chain of the miner exists, a nil chain leaves it to the caller.
+func (r *MinerReconciler) syncPodStatus(ctx context.Context, miner *appsv1alpha1.Miner, chain *appsv1alpha1.Chain) error {
 	log := log.FromContext(ctx)
 
-	pod := &corev1.Pod{}
-	if err := r.Get(ctx, client.ObjectKey{Namespace: miner.Namespace, Name: miner.Name}, pod); err != nil {
+	pod, err := r.getPod(ctx, miner)
+	if err != nil {
 		if errors.IsNotFound(err) {
 			log.Info("Pod not found, setting phase to Pending")
 			miner.Status.Phase = appsv1alpha1.MinerPhasePending
+			miner.Status.ContainerStatuses = nil
+			miner.Status.Zone = ""
+			miner.Status.Region = ""
 			condition.SetFalse(miner, condition.MinerPodHealthyCondition, condition.PodNotFoundReason, "Pod not found")
 			return nil
 		}
 		return err
 	}
 
+	if !managesPod(miner) {
+		miner.Status.PodRef = podReference(pod)
+	}
+	miner.Status.ContainerStatuses = containerStatuses(pod)
+	if err := r.syncTopology(ctx, miner, pod); err != nil {
+		return err
+	}
+
 	// Check pod phase
 	switch pod.Status.Phase {
 	case corev1.PodRunning:
-		if r.isPodReady(pod) {
+		notReady := notReadyContainers(miner.Status.ContainerStatuses)
+		switch {
+		case isPodReady(pod) && len(notReady) > 0:
+			// The aggregate PodReady condition can lag behind the container statuses
+			miner.Status.Phase = appsv1alpha1.MinerPhaseProvisioning
+			condition.MarkFalsef(miner, condition.MinerPodHealthyCondition, condition.ContainersNotReadyReason,
+				"Containers are not ready: %s", strings.Join(notReady, ", "))
+		case isPodReady(pod) && len(pod.Status.PodIPs) == 0:
+			// A ready pod may briefly have no IP, wait for one before reporting Running
+			miner.Status.Phase = appsv1alpha1.MinerPhaseProvisioning
+			condition.SetFalse(miner, condition.MinerPodHealthyCondition, condition.WaitingForAddressReason, "Pod is ready but has no IP address yet")
+		case isPodReady(pod):
+			// Only the first Running phase is observed, not a recovery or a recreated pod
+			if miner.Status.FirstRunningTime == nil {
+				now := metav1.Now()
+				// Miners already running before the field was recorded are not observed
+				if miner.Status.Phase != appsv1alpha1.MinerPhaseRunning {
+					minerTimeToReadySeconds.Observe(now.Sub(miner.CreationTimestamp.Time).Seconds())
+				}
+				miner.Status.FirstRunningTime = &now
+			}
+
+			// A pod restarted in place may recover after the miner was marked failed
 			miner.Status.Phase = appsv1alpha1.MinerPhaseRunning
+			miner.Status.FailureReason = nil
+			miner.Status.FailureMessage = nil
 			condition.SetTrue(miner, condition.MinerPodHealthyCondition)
-			condition.SetTrue(miner, condition.BootstrapReadyCondition)
+			if chain != nil {
+				condition.SetTrue(miner, condition.BootstrapReadyCondition)
+			}
 
 			// Update addresses
 			var addresses []string
@@ -288,27 +853,215 @@ func (r *MinerReconciler) syncPodStatus(ctx context.Context, miner *appsv1alpha1
 				addresses = append(addresses, addr.IP)
 			}
 			miner.Status.Addresses = addresses
-		} else {
+		default:
 			miner.Status.Phase = appsv1alpha1.MinerPhaseProvisioning
 			condition.SetFalse(miner, condition.MinerPodHealthyCondition, condition.ProvisioningReason, "Pod is not ready yet")
 		}
 	case corev1.PodPending:
+		if cs := findImagePullFailure(pod); cs != nil {
+			message := fmt.Sprintf("Container %s cannot pull image %s: %s", cs.Name, cs.Image, cs.State.Waiting.Message)
+			condition.SetFalse(miner, condition.MinerPodHealthyCondition, condition.ImagePullBackOffReason, message)
+			if time.Since(pod.CreationTimestamp.Time) > imagePullFailureThreshold {
+				miner.Status.Phase = appsv1alpha1.MinerPhaseFailed
+				reason := appsv1alpha1.MinerFailureReasonImagePullError
+				miner.Status.FailureReason = &reason
+				miner.Status.FailureMessage = &message
+			} else {
+				miner.Status.Phase = appsv1alpha1.MinerPhaseProvisioning
+			}
+			break
+		}
 		miner.Status.Phase = appsv1alpha1.MinerPhaseProvisioning
+		if cond := findUnschedulableCondition(pod); cond != nil {
+			condition.SetFalse(miner, condition.MinerPodHealthyCondition, condition.UnschedulableReason, cond.Message)
+			// The pod stays pending while it cannot be scheduled, it never reaches the Failed phase
+			if time.Since(cond.LastTransitionTime.Time) > unschedulableFailureThreshold {
+				miner.Status.Phase = appsv1alpha1.MinerPhaseFailed
+				reason := appsv1alpha1.MinerFailureReasonUnschedulable
+				miner.Status.FailureReason = &reason
+				miner.Status.FailureMessage = &cond.Message
+			}
+			break
+		}
 		condition.SetFalse(miner, condition.MinerPodHealthyCondition, condition.ProvisioningReason, "Pod is pending")
+	case corev1.PodSucceeded:
+		// The containers exited successfully and are not restarted, the miner finished its work
+		miner.Status.Phase = appsv1alpha1.MinerPhaseSucceeded
+		miner.Status.FailureReason = nil
+		miner.Status.FailureMessage = nil
+		miner.Status.Addresses = nil
+		condition.SetFalse(miner, condition.MinerPodHealthyCondition, condition.PodCompletedReason, "Pod completed successfully")
 	case corev1.PodFailed:
+		reason, message := classifyPodFailure(pod)
+		if miner.Status.Phase == appsv1alpha1.MinerPhaseFailed && miner.Status.FailureMessage != nil &&
+			strings.HasPrefix(*miner.Status.FailureMessage, message) {
+			// Logs are only read when the miner fails, keep the ones captured back then
+			message = *miner.Status.FailureMessage
+		} else {
+			message += r.failureLogs(ctx, miner, pod)
+		}
 		miner.Status.Phase = appsv1alpha1.MinerPhaseFailed
 		condition.SetFalse(miner, condition.MinerPodHealthyCondition, condition.FailedReason, "Pod failed")
-		reason := "Pod failed"
-		if pod.Status.Message != "" {
-			reason = pod.Status.Message
-		}
 		miner.Status.FailureReason = &reason
+		miner.Status.FailureMessage = &message
+	}
+
+	return nil
+}
+
+// getPod returns the pod of the miner. Externally managed pods that are not named after
+// the miner are looked up by the miner name label.
+func (r *MinerReconciler) getPod(ctx context.Context, miner *appsv1alpha1.Miner) (*corev1.Pod, error) {
+	pod := &corev1.Pod{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: miner.Namespace, Name: minerPodName(miner)}, pod)
+	if err == nil || !errors.IsNotFound(err) || managesPod(miner) {
+		return pod, err
+	}
+
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.InNamespace(miner.Namespace), client.MatchingLabels{minerNameLabel: minerPodName(miner)}); err != nil {
+		return nil, err
+	}
+	if len(podList.Items) == 0 {
+		return nil, err
+	}
+	return &podList.Items[0], nil
+}
+
+// minerPodName returns the name of the miner pod, which is also the value of its miner
+// name label. Miner names longer than a label value are truncated and suffixed with a hash
+// of the full name, so that distinct miners keep distinct pods.
+func minerPodName(miner *appsv1alpha1.Miner) string {
+	if len(miner.Name) <= maxPodNameLength {
+		return miner.Name
+	}
+
+	hasher := fnv.New32a()
+	_, _ = hasher.Write([]byte(miner.Name))
+	suffix := strconv.FormatUint(uint64(hasher.Sum32()), 36)
+	prefix := strings.TrimRight(miner.Name[:maxPodNameLength-len(suffix)-1], "-.")
+	return prefix + "-" + suffix
+}
+
+// managesPod returns true if the controller is responsible for the miner pod lifecycle.
+func managesPod(miner *appsv1alpha1.Miner) bool {
+	return miner.Spec.ManagePod == nil || *miner.Spec.ManagePod
+}
+
+// containerName returns the name of the miner container, defaulting to "miner" for
+// miners created before the field existed.
+func containerName(miner *appsv1alpha1.Miner) string {
+	if miner.Spec.ContainerName == "" {
+		return minerContainerName
+	}
+	return miner.Spec.ContainerName
+}
+
+// classifyPodFailure inspects the container statuses of a failed pod and returns the
+// failure category along with a human readable message.
+func classifyPodFailure(pod *corev1.Pod) (appsv1alpha1.MinerFailureReason, string) {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if waiting := cs.State.Waiting; waiting != nil {
+			switch {
+			case isImagePullFailure(waiting.Reason):
+				return appsv1alpha1.MinerFailureReasonImagePullError, fmt.Sprintf("Container %s: %s", cs.Name, waiting.Message)
+			case waiting.Reason == "CrashLoopBackOff":
+				return appsv1alpha1.MinerFailureReasonCrashLoop, fmt.Sprintf("Container %s: %s", cs.Name, waiting.Message)
+			}
+		}
+
+		for _, terminated := range []*corev1.ContainerStateTerminated{cs.State.Terminated, cs.LastTerminationState.Terminated} {
+			if terminated != nil && terminated.Reason == "OOMKilled" {
+				return appsv1alpha1.MinerFailureReasonOOMKilled, fmt.Sprintf("Container %s was OOMKilled", cs.Name)
+			}
+		}
+	}
+
+	message := "Pod failed"
+	if pod.Status.Message != "" {
+		message = pod.Status.Message
+	}
+	return appsv1alpha1.MinerFailureReasonUnknown, message
+}
+
+// findImagePullFailure returns the status of the first container of the pod that is
+// waiting because its image cannot be pulled, or nil if there is none.
+func findImagePullFailure(pod *corev1.Pod) *corev1.ContainerStatus {
+	for i := range pod.Status.ContainerStatuses {
+		cs := &pod.Status.ContainerStatuses[i]
+		if cs.State.Waiting != nil && isImagePullFailure(cs.State.Waiting.Reason) {
+			return cs
+		}
+	}
+	return nil
+}
+
+// findUnschedulableCondition returns the PodScheduled condition of the pod if the
+// scheduler reported that it cannot place the pod onto any node, or nil otherwise.
+func findUnschedulableCondition(pod *corev1.Pod) *corev1.PodCondition {
+	for i := range pod.Status.Conditions {
+		cond := &pod.Status.Conditions[i]
+		if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse &&
+			cond.Reason == corev1.PodReasonUnschedulable {
+			return cond
+		}
+	}
+	return nil
+}
+
+// syncTopology reports the zone and region of the node the miner pod is scheduled on.
+func (r *MinerReconciler) syncTopology(ctx context.Context, miner *appsv1alpha1.Miner, pod *corev1.Pod) error {
+	miner.Status.Zone = ""
+	miner.Status.Region = ""
+	if pod.Spec.NodeName == "" {
+		return nil
 	}
 
+	node := &corev1.Node{}
+	if err := r.Get(ctx, client.ObjectKey{Name: pod.Spec.NodeName}, node); err != nil {
+		// The node may have been removed while the pod still references it
+		return client.IgnoreNotFound(err)
+	}
+	miner.Status.Zone = node.Labels[corev1.LabelTopologyZone]
+	miner.Status.Region = node.Labels[corev1.LabelTopologyRegion]
 	return nil
 }
 
-func (r *MinerReconciler) isPodReady(pod *corev1.Pod) bool {
+// containerStatuses returns the readiness and restart count of each container of the pod.
+func containerStatuses(pod *corev1.Pod) []appsv1alpha1.MinerContainerStatus {
+	var statuses []appsv1alpha1.MinerContainerStatus
+	for _, cs := range pod.Status.ContainerStatuses {
+		statuses = append(statuses, appsv1alpha1.MinerContainerStatus{
+			Name:         cs.Name,
+			Ready:        cs.Ready,
+			RestartCount: cs.RestartCount,
+		})
+	}
+	return statuses
+}
+
+// notReadyContainers returns the names of the containers that are not ready.
+func notReadyContainers(statuses []appsv1alpha1.MinerContainerStatus) []string {
+	var names []string
+	for _, cs := range statuses {
+		if !cs.Ready {
+			names = append(names, cs.Name)
+		}
+	}
+	return names
+}
+
+// isImagePullFailure returns true if the container waiting reason indicates that
+// the image cannot be pulled.
+func isImagePullFailure(reason string) bool {
+	switch reason {
+	case "ErrImagePull", "ImagePullBackOff", "InvalidImageName":
+		return true
+	}
+	return false
+}
+
+func isPodReady(pod *corev1.Pod) bool {
 	for _, condition := range pod.Status.Conditions {
 		if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
 			return true
@@ -317,10 +1070,26 @@ func (r *MinerReconciler) isPodReady(pod *corev1.Pod) bool {
 	return false
 }
 
+// minerChangedPredicate ignores updates of a Miner that leave its generation, labels and
+// annotations unchanged, such as the status writes of the controller itself.
+var minerChangedPredicate = predicate.Or(
+	predicate.GenerationChangedPredicate{},
+	predicate.LabelChangedPredicate{},
+	predicate.AnnotationChangedPredicate{},
+)
+
 // SetupWithManager sets up the controller with the Manager.
-func (r *MinerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+func (r *MinerReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
+	// Nodes are read from the cache for the miner topology. Register their informer with the
+	// manager so that it syncs at startup rather than blocking the first reconcile reading a node.
+	if _, err := mgr.GetCache().GetInformer(context.Background(), &corev1.Node{}); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&appsv1alpha1.Miner{}).
+		For(&appsv1alpha1.Miner{}, builder.WithPredicates(minerChangedPredicate)).
+		Owns(&corev1.Pod{}).
 		Named("miner").
+		WithOptions(options).
 		Complete(r)
 }
@@ -18,11 +18,23 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	corev1 "k8s.io/api/core/v1"
@@ -90,6 +102,24 @@ var _ = Describe("Miner Controller", func() {
 			Expect(miner.Status.PodRef).NotTo(BeNil())
 		})
 
+		It("should not add a finalizer when finalizers are disabled", func() {
+			controllerReconciler := &MinerReconciler{
+				Client:            k8sClient,
+				Scheme:            k8sClient.Scheme(),
+				DisableFinalizers: true,
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			Expect(miner.Finalizers).To(BeEmpty())
+			Expect(miner.Status.PodRef).NotTo(BeNil())
+		})
+
 		It("should update status when pod is ready", func() {
 			By("Creating a ready pod")
 			pod := &corev1.Pod{
@@ -105,17 +135,20 @@ var _ = Describe("Miner Controller", func() {
 						{Name: "miner", Image: "nginx:alpine"},
 					},
 				},
-				Status: corev1.PodStatus{
-					Phase: corev1.PodRunning,
-					Conditions: []corev1.PodCondition{
-						{
-							Type:   corev1.PodReady,
-							Status: corev1.ConditionTrue,
-						},
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			pod.Status = corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				Conditions: []corev1.PodCondition{
+					{
+						Type:   corev1.PodReady,
+						Status: corev1.ConditionTrue,
 					},
 				},
+				PodIP:  "10.0.0.1",
+				PodIPs: []corev1.PodIP{{IP: "10.0.0.1"}},
 			}
-			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
 
 			By("Reconciling the resource")
 			controllerReconciler := &MinerReconciler{
@@ -144,8 +177,10 @@ var _ = Describe("Miner Controller", func() {
 			Expect(hasReadyCondition).To(BeTrue())
 		})
 
-		It("should handle deletion correctly", func() {
-			By("Creating a pod")
+		It("should observe the time to ready when the miner first becomes Running", func() {
+			before := histogramSampleCount(minerTimeToReadySeconds)
+
+			By("Creating a ready pod")
 			pod := &corev1.Pod{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      resourceName,
@@ -158,13 +193,76 @@ var _ = Describe("Miner Controller", func() {
 				},
 			}
 			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			pod.Status = corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				Conditions: []corev1.PodCondition{
+					{
+						Type:   corev1.PodReady,
+						Status: corev1.ConditionTrue,
+					},
+				},
+				PodIP:  "10.0.0.1",
+				PodIPs: []corev1.PodIP{{IP: "10.0.0.1"}},
+			}
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
 
-			By("Deleting the miner")
+			// The chain of the miner does not exist, so BootstrapReady never becomes true
+			// and cannot tell whether the miner was already observed
+			By("Reconciling the resource twice")
+			controllerReconciler := &MinerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("Checking a single observation was recorded")
 			miner := &appsv1alpha1.Miner{}
 			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
-			Expect(k8sClient.Delete(ctx, miner)).To(Succeed())
+			Expect(miner.Status.Phase).To(Equal(appsv1alpha1.MinerPhaseRunning))
+			Expect(histogramSampleCount(minerTimeToReadySeconds)).To(Equal(before + 1))
+		})
 
-			By("Reconciling deletion")
+		It("should report the zone and region of the node the pod is scheduled on", func() {
+			By("Creating a node with topology labels")
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-miner-node",
+					Labels: map[string]string{
+						corev1.LabelTopologyZone:   "eu-west-1a",
+						corev1.LabelTopologyRegion: "eu-west-1",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, node)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, node))).To(Succeed())
+			})
+
+			By("Creating a pod scheduled on the node")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: corev1.PodSpec{
+					NodeName: node.Name,
+					Containers: []corev1.Container{
+						{Name: "miner", Image: "nginx:alpine"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, pod, client.GracePeriodSeconds(0)))).To(Succeed())
+			})
+
+			By("Reconciling the resource")
 			controllerReconciler := &MinerReconciler{
 				Client: k8sClient,
 				Scheme: k8sClient.Scheme(),
@@ -175,11 +273,1661 @@ var _ = Describe("Miner Controller", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 
-			By("Checking if pod was deleted")
-			Eventually(func() bool {
-				podErr := k8sClient.Get(ctx, typeNamespacedName, &corev1.Pod{})
-				return errors.IsNotFound(podErr)
-			}, "10s").Should(BeTrue())
+			By("Checking the topology in the Miner status")
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			Expect(miner.Status.Zone).To(Equal("eu-west-1a"))
+			Expect(miner.Status.Region).To(Equal("eu-west-1"))
 		})
-	})
-})
+
+		It("should stay Provisioning while a ready pod has no IP address", func() {
+			By("Creating a ready pod without IPs")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "miner", Image: "nginx:alpine"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			pod.Status = corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				Conditions: []corev1.PodCondition{
+					{
+						Type:   corev1.PodReady,
+						Status: corev1.ConditionTrue,
+					},
+				},
+			}
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+			By("Reconciling the resource")
+			controllerReconciler := &MinerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking Miner status is still Provisioning")
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			Expect(miner.Status.Phase).To(Equal(appsv1alpha1.MinerPhaseProvisioning))
+			Expect(miner.Status.Addresses).To(BeEmpty())
+			Expect(condition.Get(miner, condition.MinerPodHealthyCondition).Reason).To(Equal(string(condition.WaitingForAddressReason)))
+		})
+
+		It("should not write status when nothing changed", func() {
+			By("Reconciling the resource until the status settles")
+			countingClient := &statusWriteCountingClient{Client: k8sClient}
+			controllerReconciler := &MinerReconciler{
+				Client: countingClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(countingClient.statusWrites).To(Equal(1))
+
+			By("Reconciling again without any change")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(countingClient.statusWrites).To(Equal(1))
+		})
+
+		It("should not create a pod for an unknown miner type", func() {
+			By("Adding the finalizer so reconcile does not write the spec")
+			resource := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			controllerutil.AddFinalizer(resource, minerFinalizer)
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			By("Reconciling with a client that reports minerType \"hgue\"")
+			controllerReconciler := &MinerReconciler{
+				Client: &minerTypeOverrideClient{Client: k8sClient, minerType: "hgue"},
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking no pod was created")
+			pod := &corev1.Pod{}
+			err = k8sClient.Get(ctx, typeNamespacedName, pod)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			By("Checking the InfrastructureReady condition")
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			Expect(condition.IsFalse(miner, condition.InfrastructureReadyCondition)).To(BeTrue())
+			cond := condition.Get(miner, condition.InfrastructureReadyCondition)
+			Expect(cond.Reason).To(Equal(string(condition.InvalidConfigurationReason)))
+			Expect(cond.Message).To(ContainSubstring(`"hgue"`))
+		})
+
+		It("should add sidecars after the miner container", func() {
+			By("Adding a sidecar to the miner")
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			miner.Spec.Sidecars = []corev1.Container{{Name: "exporter", Image: "prom/node-exporter"}}
+			Expect(k8sClient.Update(ctx, miner)).To(Succeed())
+
+			By("Reconciling the resource")
+			controllerReconciler := &MinerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the pod containers")
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, pod)).To(Succeed())
+			Expect(pod.Spec.Containers).To(HaveLen(2))
+			Expect(pod.Spec.Containers[0].Name).To(Equal("miner"))
+			Expect(pod.Spec.Containers[1].Name).To(Equal("exporter"))
+			Expect(pod.Spec.Containers[1].Image).To(Equal("prom/node-exporter"))
+		})
+
+		It("should name the miner container after the spec", func() {
+			By("Setting a container name on the miner")
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			miner.Spec.ContainerName = "worker"
+			Expect(k8sClient.Update(ctx, miner)).To(Succeed())
+
+			By("Reconciling the resource")
+			controllerReconciler := &MinerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the pod containers")
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, pod)).To(Succeed())
+			Expect(pod.Spec.Containers).To(HaveLen(1))
+			Expect(pod.Spec.Containers[0].Name).To(Equal("worker"))
+		})
+
+		It("should truncate the pod name of a miner with a very long name", func() {
+			By("Creating a miner with a name longer than a label value")
+			longName := types.NamespacedName{
+				Name:      strings.Repeat("long-minerset-name-", 4) + "x7k2p",
+				Namespace: "default",
+			}
+			miner := &appsv1alpha1.Miner{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      longName.Name,
+					Namespace: longName.Namespace,
+				},
+				Spec: appsv1alpha1.MinerSpec{
+					ChainName: "test-chain",
+					MinerType: appsv1alpha1.MinerTypeSmall,
+				},
+			}
+			Expect(k8sClient.Create(ctx, miner)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, miner)).To(Succeed())
+			})
+
+			By("Reconciling the miner")
+			controllerReconciler := &MinerReconciler{
+				Client:            k8sClient,
+				Scheme:            k8sClient.Scheme(),
+				DisableFinalizers: true,
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: longName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the pod was created with a truncated name")
+			podName := minerPodName(miner)
+			Expect(len(podName)).To(BeNumerically("<=", maxPodNameLength))
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: podName, Namespace: "default"}, pod)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, pod))).To(Succeed())
+			})
+
+			By("Checking the truncation is reported")
+			Expect(k8sClient.Get(ctx, longName, miner)).To(Succeed())
+			Expect(miner.Status.PodRef).NotTo(BeNil())
+			Expect(miner.Status.PodRef.Name).To(Equal(podName))
+			cond := condition.Get(miner, condition.PodNameTruncatedCondition)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Message).To(ContainSubstring(podName))
+		})
+
+		It("should set init containers on the pod", func() {
+			By("Creating the chain of the miner with its ConfigMap")
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-chain-config",
+					Namespace: "default",
+				},
+				Data: map[string]string{"chainName": "test-chain"},
+			}
+			Expect(k8sClient.Create(ctx, configMap)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, configMap)).To(Succeed())
+			})
+			chain := &appsv1alpha1.Chain{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-chain",
+					Namespace: "default",
+				},
+				Spec: appsv1alpha1.ChainSpec{
+					Image: "nginx:alpine",
+				},
+			}
+			Expect(k8sClient.Create(ctx, chain)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, chain)).To(Succeed())
+			})
+			chain.Status.ConfigMapRef = &appsv1alpha1.LocalObjectReference{Name: "test-chain-config"}
+			Expect(k8sClient.Status().Update(ctx, chain)).To(Succeed())
+
+			By("Adding init containers to the miner")
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			miner.Spec.ChainConfigInitContainer = true
+			miner.Spec.InitContainers = []corev1.Container{{Name: "download", Image: "busybox"}}
+			Expect(k8sClient.Update(ctx, miner)).To(Succeed())
+
+			By("Reconciling the resource")
+			controllerReconciler := &MinerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the init containers and the chain config volume")
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, pod)).To(Succeed())
+			Expect(pod.Spec.InitContainers).To(HaveLen(2))
+			Expect(pod.Spec.InitContainers[0].Name).To(Equal(chainConfigVolumeName))
+			Expect(pod.Spec.InitContainers[0].VolumeMounts).To(ContainElement(HaveField("MountPath", chainConfigMountPath)))
+			Expect(pod.Spec.InitContainers[1].Name).To(Equal("download"))
+			Expect(pod.Spec.Volumes).To(ContainElement(HaveField("ConfigMap.Name", "test-chain-config")))
+		})
+
+		It("should wait for the chain ConfigMap to exist before creating the pod", func() {
+			By("Creating the chain of the miner referencing a missing ConfigMap")
+			chain := &appsv1alpha1.Chain{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-chain",
+					Namespace: "default",
+				},
+				Spec: appsv1alpha1.ChainSpec{
+					Image: "nginx:alpine",
+				},
+			}
+			Expect(k8sClient.Create(ctx, chain)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, chain)).To(Succeed())
+			})
+			chain.Status.ConfigMapRef = &appsv1alpha1.LocalObjectReference{Name: "test-chain-config"}
+			Expect(k8sClient.Status().Update(ctx, chain)).To(Succeed())
+
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			miner.Spec.ChainConfigInitContainer = true
+			Expect(k8sClient.Update(ctx, miner)).To(Succeed())
+
+			By("Reconciling the resource")
+			controllerReconciler := &MinerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(chainReadyRequeueInterval))
+
+			By("Checking the miner waits without a pod")
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &corev1.Pod{}))).To(BeTrue())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			cond := condition.Get(miner, condition.BootstrapReadyCondition)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(string(condition.WaitingForConfigMapReason)))
+
+			By("Creating the ConfigMap")
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-chain-config",
+					Namespace: "default",
+				},
+				Data: map[string]string{"chainName": "test-chain"},
+			}
+			Expect(k8sClient.Create(ctx, configMap)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, configMap)).To(Succeed())
+			})
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the pod was created")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &corev1.Pod{})).To(Succeed())
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+					Name: resourceName, Namespace: "default",
+				}}))).To(Succeed())
+			})
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			Expect(condition.Has(miner, condition.BootstrapReadyCondition)).To(BeFalse())
+		})
+
+		It("should recreate the pod when the chain ConfigMap changes", func() {
+			By("Creating the chain of the miner with its ConfigMap")
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-chain-config",
+					Namespace: "default",
+				},
+				Data: map[string]string{"chainName": "test-chain"},
+			}
+			Expect(k8sClient.Create(ctx, configMap)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, configMap)).To(Succeed())
+			})
+			chain := &appsv1alpha1.Chain{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-chain",
+					Namespace: "default",
+				},
+				Spec: appsv1alpha1.ChainSpec{
+					Image: "nginx:alpine",
+				},
+			}
+			Expect(k8sClient.Create(ctx, chain)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, chain)).To(Succeed())
+			})
+			chain.Status.ConfigMapRef = &appsv1alpha1.LocalObjectReference{Name: configMap.Name}
+			Expect(k8sClient.Status().Update(ctx, chain)).To(Succeed())
+
+			By("Reconciling the resource")
+			controllerReconciler := &MinerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, pod)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+					Name: resourceName, Namespace: "default",
+				}}))).To(Succeed())
+			})
+			hash, err := configHash(configMap.Data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pod.Annotations).To(HaveKeyWithValue(configChecksumAnnotation, hash))
+			originalUID := pod.UID
+
+			By("Changing the chain ConfigMap")
+			configMap.Data["chainName"] = "renamed-chain"
+			Expect(k8sClient.Update(ctx, configMap)).To(Succeed())
+
+			By("Reconciling until the pod is recreated")
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(podRestartRequeueInterval))
+
+			hash, err = configHash(configMap.Data)
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(func(g Gomega) {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				g.Expect(err).NotTo(HaveOccurred())
+
+				recreated := &corev1.Pod{}
+				g.Expect(k8sClient.Get(ctx, typeNamespacedName, recreated)).To(Succeed())
+				g.Expect(recreated.UID).NotTo(Equal(originalUID))
+				g.Expect(recreated.Annotations).To(HaveKeyWithValue(configChecksumAnnotation, hash))
+			}, "10s").Should(Succeed())
+		})
+
+		It("should recreate the pod when the restart policy changes", func() {
+			By("Reconciling the resource")
+			controllerReconciler := &MinerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, pod)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+					Name: resourceName, Namespace: "default",
+				}}))).To(Succeed())
+			})
+			Expect(pod.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyAlways))
+			originalUID := pod.UID
+
+			By("Changing the restart policy of the miner")
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			miner.Spec.RestartPolicy = corev1.RestartPolicyNever
+			Expect(k8sClient.Update(ctx, miner)).To(Succeed())
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(podRestartRequeueInterval))
+
+			By("Checking the InfrastructureReady condition during the transition")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			cond := condition.Get(miner, condition.InfrastructureReadyCondition)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(string(condition.RestartPolicyChangedReason)))
+
+			By("Reconciling until the pod is recreated")
+			Eventually(func(g Gomega) {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				g.Expect(err).NotTo(HaveOccurred())
+
+				recreated := &corev1.Pod{}
+				g.Expect(k8sClient.Get(ctx, typeNamespacedName, recreated)).To(Succeed())
+				g.Expect(recreated.UID).NotTo(Equal(originalUID))
+				g.Expect(recreated.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+			}, "10s").Should(Succeed())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			Expect(condition.IsTrue(miner, condition.InfrastructureReadyCondition)).To(BeTrue())
+		})
+
+		It("should report a deleted chain on the BootstrapReady condition", func() {
+			By("Creating and deleting the chain of the miner")
+			chain := &appsv1alpha1.Chain{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-chain",
+					Namespace: "default",
+				},
+				Spec: appsv1alpha1.ChainSpec{
+					Image: "nginx:alpine",
+				},
+			}
+			Expect(k8sClient.Create(ctx, chain)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, chain)).To(Succeed())
+
+			By("Reconciling the resource")
+			controllerReconciler := &MinerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the BootstrapReady condition")
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			cond := condition.Get(miner, condition.BootstrapReadyCondition)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(string(condition.ChainNotFoundReason)))
+			Expect(cond.Message).To(ContainSubstring(`"test-chain"`))
+		})
+
+		It("should set image pull secrets from the miner and its chain on the pod", func() {
+			By("Creating the chain of the miner with pull secrets")
+			chain := &appsv1alpha1.Chain{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-chain",
+					Namespace: "default",
+				},
+				Spec: appsv1alpha1.ChainSpec{
+					Image:            "nginx:alpine",
+					ImagePullSecrets: []corev1.LocalObjectReference{{Name: "chain-secret"}, {Name: "shared-secret"}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, chain)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, chain)).To(Succeed())
+			})
+
+			By("Setting pull secrets on the miner")
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			miner.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "miner-secret"}, {Name: "shared-secret"}}
+			Expect(k8sClient.Update(ctx, miner)).To(Succeed())
+
+			By("Reconciling the resource")
+			controllerReconciler := &MinerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the pull secrets on the pod")
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, pod)).To(Succeed())
+			Expect(pod.Spec.ImagePullSecrets).To(Equal([]corev1.LocalObjectReference{
+				{Name: "miner-secret"}, {Name: "shared-secret"}, {Name: "chain-secret"},
+			}))
+		})
+
+		It("should recover a failed miner whose pod becomes ready again", func() {
+			By("Marking the miner as failed")
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			reason := appsv1alpha1.MinerFailureReasonCrashLoop
+			message := "Container miner: back-off restarting failed container"
+			miner.Status.Phase = appsv1alpha1.MinerPhaseFailed
+			miner.Status.FailureReason = &reason
+			miner.Status.FailureMessage = &message
+			Expect(k8sClient.Status().Update(ctx, miner)).To(Succeed())
+
+			By("Creating a pod that was restarted and is ready")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyOnFailure,
+					Containers: []corev1.Container{
+						{Name: "miner", Image: "nginx:alpine"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			pod.Status = corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+				},
+				PodIP:  "10.0.0.1",
+				PodIPs: []corev1.PodIP{{IP: "10.0.0.1"}},
+			}
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+			By("Reconciling the resource")
+			controllerReconciler := &MinerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the miner is Running again")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			Expect(miner.Status.Phase).To(Equal(appsv1alpha1.MinerPhaseRunning))
+			Expect(miner.Status.FailureReason).To(BeNil())
+			Expect(miner.Status.FailureMessage).To(BeNil())
+			Expect(miner.Status.Ready).To(BeTrue())
+		})
+
+		It("should report a miner whose pod completed as Succeeded", func() {
+			By("Making the miner run its pod to completion")
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			miner.Spec.RestartPolicy = corev1.RestartPolicyNever
+			Expect(k8sClient.Update(ctx, miner)).To(Succeed())
+
+			By("Creating a pod that succeeded")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{Name: "miner", Image: "nginx:alpine"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, pod, client.GracePeriodSeconds(0)))).To(Succeed())
+			})
+			pod.Status = corev1.PodStatus{
+				Phase: corev1.PodSucceeded,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "miner",
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0, Reason: "Completed"}},
+				}},
+			}
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+			By("Reconciling the resource")
+			controllerReconciler := &MinerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the miner is Succeeded and the pod is kept")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			Expect(miner.Status.Phase).To(Equal(appsv1alpha1.MinerPhaseSucceeded))
+			Expect(miner.Status.FailureReason).To(BeNil())
+			Expect(miner.Status.Ready).To(BeFalse())
+			cond := condition.Get(miner, condition.MinerPodHealthyCondition)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(string(condition.PodCompletedReason)))
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pod), pod)).To(Succeed())
+			Expect(pod.DeletionTimestamp).To(BeNil())
+		})
+
+		It("should surface ImagePullBackOff on the PodHealthy condition", func() {
+			By("Creating a pod whose image cannot be pulled")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "miner", Image: "does-not-exist:latest"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+
+			pod.Status = corev1.PodStatus{
+				Phase: corev1.PodPending,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "miner",
+					Image: "does-not-exist:latest",
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{
+							Reason:  "ImagePullBackOff",
+							Message: "Back-off pulling image",
+						},
+					},
+				}},
+			}
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+			By("Reconciling the resource")
+			controllerReconciler := &MinerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the PodHealthy condition")
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			Expect(miner.Status.Phase).To(Equal(appsv1alpha1.MinerPhaseProvisioning))
+			cond := condition.Get(miner, condition.MinerPodHealthyCondition)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(string(condition.ImagePullBackOffReason)))
+		})
+
+		It("should surface an unschedulable pod on the PodHealthy condition", func() {
+			By("Creating a pod the scheduler cannot place")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "miner", Image: "nginx:alpine"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+
+			message := "0/3 nodes are available: 3 Insufficient cpu."
+			pod.Status = corev1.PodStatus{
+				Phase: corev1.PodPending,
+				Conditions: []corev1.PodCondition{{
+					Type:    corev1.PodScheduled,
+					Status:  corev1.ConditionFalse,
+					Reason:  corev1.PodReasonUnschedulable,
+					Message: message,
+				}},
+			}
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+			By("Reconciling the resource")
+			controllerReconciler := &MinerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the PodHealthy condition")
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			Expect(miner.Status.Phase).To(Equal(appsv1alpha1.MinerPhaseProvisioning))
+			cond := condition.Get(miner, condition.MinerPodHealthyCondition)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(string(condition.UnschedulableReason)))
+			Expect(cond.Message).To(Equal(message))
+		})
+
+		It("should not report a healthy pod while a sidecar is not ready", func() {
+			By("Creating a running pod with an unready sidecar")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "miner", Image: "nginx:alpine"},
+						{Name: "exporter", Image: "exporter:latest"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+
+			pod.Status = corev1.PodStatus{
+				Phase:  corev1.PodRunning,
+				PodIPs: []corev1.PodIP{{IP: "10.0.0.1"}},
+				Conditions: []corev1.PodCondition{{
+					Type:   corev1.PodReady,
+					Status: corev1.ConditionTrue,
+				}},
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "miner", Image: "nginx:alpine", Ready: true},
+					{Name: "exporter", Image: "exporter:latest", Ready: false},
+				},
+			}
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+			By("Reconciling the resource")
+			controllerReconciler := &MinerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the container statuses and the PodHealthy condition")
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			Expect(miner.Status.ContainerStatuses).To(ConsistOf(
+				appsv1alpha1.MinerContainerStatus{Name: "miner", Ready: true},
+				appsv1alpha1.MinerContainerStatus{Name: "exporter", Ready: false},
+			))
+			Expect(miner.Status.Phase).To(Equal(appsv1alpha1.MinerPhaseProvisioning))
+			Expect(miner.Status.Ready).To(BeFalse())
+			cond := condition.Get(miner, condition.MinerPodHealthyCondition)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(string(condition.ContainersNotReadyReason)))
+			Expect(cond.Message).To(ContainSubstring("exporter"))
+		})
+
+		It("should treat an already existing pod as created", func() {
+			By("Creating a pod with the same name as the miner")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "miner", Image: "nginx:alpine"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+
+			By("Reconciling with a client that has not observed the pod yet")
+			controllerReconciler := &MinerReconciler{
+				Client: &stalePodClient{Client: k8sClient},
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the InfrastructureReady condition and PodRef")
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			Expect(condition.IsTrue(miner, condition.InfrastructureReadyCondition)).To(BeTrue())
+			Expect(miner.Status.PodRef).NotTo(BeNil())
+			Expect(miner.Status.PodRef.Name).To(Equal(resourceName))
+			Expect(miner.Status.PodCreationFailures).To(BeZero())
+		})
+
+		It("should not adopt an already existing pod controlled by another object", func() {
+			By("Creating a pod with the same name as the miner controlled by a ReplicaSet")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: "apps/v1",
+						Kind:       "ReplicaSet",
+						Name:       "other",
+						UID:        "other-uid",
+						Controller: ptr.To(true),
+					}},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "miner", Image: "nginx:alpine"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+
+			By("Reconciling with a client that has not observed the pod yet")
+			controllerReconciler := &MinerReconciler{
+				Client: &stalePodClient{Client: k8sClient},
+				Scheme: k8sClient.Scheme(),
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(podConflictRequeueInterval))
+
+			By("Checking the conflict is reported")
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			Expect(miner.Status.PodRef).To(BeNil())
+			cond := condition.Get(miner, condition.InfrastructureReadyCondition)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(string(condition.PodConflictReason)))
+		})
+
+		It("should track an externally managed pod without creating one", func() {
+			By("Disabling pod management on the miner")
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			managePod := false
+			miner.Spec.ManagePod = &managePod
+			Expect(k8sClient.Update(ctx, miner)).To(Succeed())
+
+			By("Injecting an externally managed pod labeled with the miner name")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "external-miner-pod",
+					Namespace: "default",
+					Labels: map[string]string{
+						minerNameLabel: resourceName,
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "miner", Image: "nginx:alpine"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, pod)).To(Succeed())
+			})
+
+			pod.Status = corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+				},
+			}
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+			By("Reconciling the resource")
+			controllerReconciler := &MinerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking no pod was created for the miner")
+			err = k8sClient.Get(ctx, typeNamespacedName, &corev1.Pod{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			By("Checking the status tracks the injected pod")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			Expect(miner.Status.Phase).To(Equal(appsv1alpha1.MinerPhaseRunning))
+			Expect(miner.Status.PodRef).NotTo(BeNil())
+			Expect(miner.Status.PodRef.Name).To(Equal("external-miner-pod"))
+		})
+
+		It("should back off exponentially on repeated pod creation failures", func() {
+			By("Reconciling with a client that fails to create pods")
+			controllerReconciler := &MinerReconciler{
+				Client: &podCreateFailingClient{Client: k8sClient},
+				Scheme: k8sClient.Scheme(),
+			}
+
+			var intervals []time.Duration
+			for i := 0; i < 3; i++ {
+				result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				intervals = append(intervals, result.RequeueAfter)
+			}
+
+			By("Checking requeue intervals are increasing")
+			Expect(intervals[0]).To(Equal(podCreationBaseBackoff))
+			Expect(intervals[1]).To(BeNumerically(">", intervals[0]))
+			Expect(intervals[2]).To(BeNumerically(">", intervals[1]))
+
+			By("Checking failures are recorded in status")
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			Expect(miner.Status.PodCreationFailures).To(Equal(int32(3)))
+			Expect(condition.IsFalse(miner, condition.InfrastructureReadyCondition)).To(BeTrue())
+			Expect(condition.Get(miner, condition.InfrastructureReadyCondition).Message).To(ContainSubstring("3 consecutive failures"))
+		})
+
+		It("should wait for the pod to drain before deleting it", func() {
+			By("Creating a pod")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "miner", Image: "nginx:alpine"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+
+			By("Deleting a miner with a drain timeout")
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			miner.Spec.DrainTimeoutSeconds = ptr.To[int32](60)
+			controllerutil.AddFinalizer(miner, minerFinalizer)
+			Expect(k8sClient.Update(ctx, miner)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, miner)).To(Succeed())
+
+			controllerReconciler := &MinerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("~", 60*time.Second, 5*time.Second))
+
+			By("Checking the drain was requested")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, pod)).To(Succeed())
+			Expect(pod.Annotations).To(HaveKeyWithValue(drainRequestedAnnotation, "true"))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			Expect(miner.Status.Phase).To(Equal(appsv1alpha1.MinerPhaseDraining))
+			Expect(condition.Get(miner, condition.MinerPodHealthyCondition).Reason).To(Equal(string(condition.DrainingReason)))
+
+			By("Completing the drain")
+			pod.Annotations[drainedAnnotation] = "true"
+			Expect(k8sClient.Update(ctx, pod)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the pod and the miner were deleted")
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &corev1.Pod{}))
+			}, "10s").Should(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &appsv1alpha1.Miner{}))).To(BeTrue())
+		})
+
+		It("should delete the pod once the drain timeout has passed", func() {
+			By("Creating a pod")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "miner", Image: "nginx:alpine"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+
+			By("Deleting a miner with a short drain timeout")
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			miner.Spec.DrainTimeoutSeconds = ptr.To[int32](1)
+			controllerutil.AddFinalizer(miner, minerFinalizer)
+			Expect(k8sClient.Update(ctx, miner)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, miner)).To(Succeed())
+
+			controllerReconciler := &MinerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			By("Reconciling until the pod is deleted without it ever reporting a drain")
+			Eventually(func(g Gomega) {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &corev1.Pod{}))).To(BeTrue())
+			}, "10s", "500ms").Should(Succeed())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &appsv1alpha1.Miner{}))).To(BeTrue())
+		})
+
+		It("should handle deletion correctly", func() {
+			By("Creating a pod")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "miner", Image: "nginx:alpine"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+
+			By("Deleting the miner")
+			miner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, miner)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, miner)).To(Succeed())
+
+			By("Reconciling deletion")
+			controllerReconciler := &MinerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking if pod was deleted")
+			Eventually(func() bool {
+				podErr := k8sClient.Get(ctx, typeNamespacedName, &corev1.Pod{})
+				return errors.IsNotFound(podErr)
+			}, "10s").Should(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("syncPodStatus", func() {
+	It("should keep BootstrapReady false for a ready miner whose chain does not exist", func() {
+		miner := &appsv1alpha1.Miner{
+			ObjectMeta: metav1.ObjectMeta{Name: "orphaned-miner", Namespace: "default"},
+			Spec:       appsv1alpha1.MinerSpec{ChainName: "missing-chain"},
+		}
+		condition.MarkFalsef(miner, condition.BootstrapReadyCondition, condition.ChainNotFoundReason,
+			"Chain %q does not exist", miner.Spec.ChainName)
+		observed := condition.Get(miner, condition.BootstrapReadyCondition).DeepCopy()
+
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: minerPodName(miner), Namespace: "default"},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+				PodIPs:     []corev1.PodIP{{IP: "10.0.0.1"}},
+			},
+		}
+		r := &MinerReconciler{Client: fake.NewClientBuilder().WithScheme(k8sClient.Scheme()).WithObjects(pod).Build()}
+
+		Expect(r.syncPodStatus(ctx, miner, nil)).To(Succeed())
+
+		Expect(miner.Status.Phase).To(Equal(appsv1alpha1.MinerPhaseRunning))
+		Expect(condition.Get(miner, condition.BootstrapReadyCondition)).To(Equal(observed))
+	})
+
+	It("should fail a miner whose pod stays unschedulable", func() {
+		miner := &appsv1alpha1.Miner{
+			ObjectMeta: metav1.ObjectMeta{Name: "unschedulable-miner", Namespace: "default"},
+			Spec:       appsv1alpha1.MinerSpec{ChainName: "chain"},
+		}
+		message := "0/3 nodes are available: 3 Insufficient cpu."
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: minerPodName(miner), Namespace: "default"},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				Conditions: []corev1.PodCondition{{
+					Type:               corev1.PodScheduled,
+					Status:             corev1.ConditionFalse,
+					Reason:             corev1.PodReasonUnschedulable,
+					Message:            message,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-unschedulableFailureThreshold - time.Minute)),
+				}},
+			},
+		}
+		r := &MinerReconciler{Client: fake.NewClientBuilder().WithScheme(k8sClient.Scheme()).WithObjects(pod).Build()}
+
+		Expect(r.syncPodStatus(ctx, miner, nil)).To(Succeed())
+
+		Expect(miner.Status.Phase).To(Equal(appsv1alpha1.MinerPhaseFailed))
+		Expect(miner.Status.FailureReason).To(Equal(ptr.To(appsv1alpha1.MinerFailureReasonUnschedulable)))
+		Expect(miner.Status.FailureMessage).To(Equal(ptr.To(message)))
+		cond := condition.Get(miner, condition.MinerPodHealthyCondition)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Reason).To(Equal(string(condition.UnschedulableReason)))
+	})
+
+	It("should observe the time to ready once when the miner recovers from a failure", func() {
+		miner := &appsv1alpha1.Miner{
+			ObjectMeta: metav1.ObjectMeta{Name: "recovering-miner", Namespace: "default"},
+			Spec:       appsv1alpha1.MinerSpec{ChainName: "chain"},
+		}
+		running := corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			PodIPs:     []corev1.PodIP{{IP: "10.0.0.1"}},
+		}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: minerPodName(miner), Namespace: "default"},
+			Status:     running,
+		}
+		r := &MinerReconciler{Client: fake.NewClientBuilder().WithScheme(k8sClient.Scheme()).WithObjects(pod).Build()}
+		before := histogramSampleCount(minerTimeToReadySeconds)
+
+		By("Running, failing and running again")
+		Expect(r.syncPodStatus(ctx, miner, nil)).To(Succeed())
+		Expect(miner.Status.Phase).To(Equal(appsv1alpha1.MinerPhaseRunning))
+		firstRunningTime := miner.Status.FirstRunningTime
+		Expect(firstRunningTime).NotTo(BeNil())
+
+		pod.Status = corev1.PodStatus{Phase: corev1.PodFailed}
+		Expect(r.Update(ctx, pod)).To(Succeed())
+		Expect(r.syncPodStatus(ctx, miner, nil)).To(Succeed())
+		Expect(miner.Status.Phase).To(Equal(appsv1alpha1.MinerPhaseFailed))
+
+		pod.Status = running
+		Expect(r.Update(ctx, pod)).To(Succeed())
+		Expect(r.syncPodStatus(ctx, miner, nil)).To(Succeed())
+		Expect(miner.Status.Phase).To(Equal(appsv1alpha1.MinerPhaseRunning))
+
+		Expect(histogramSampleCount(minerTimeToReadySeconds)).To(Equal(before + 1))
+		Expect(miner.Status.FirstRunningTime).To(Equal(firstRunningTime))
+	})
+})
+
+var _ = Describe("minerChangedPredicate", func() {
+	newMiner := func() *appsv1alpha1.Miner {
+		return &appsv1alpha1.Miner{ObjectMeta: metav1.ObjectMeta{
+			Name:            "miner",
+			Namespace:       "default",
+			Generation:      1,
+			ResourceVersion: "1",
+		}}
+	}
+
+	It("should not enqueue a no-op metadata or status update", func() {
+		oldMiner := newMiner()
+
+		metadataOnly := oldMiner.DeepCopy()
+		metadataOnly.ResourceVersion = "2"
+		metadataOnly.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "kubectl"}}
+		Expect(minerChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldMiner, ObjectNew: metadataOnly})).To(BeFalse())
+
+		statusOnly := oldMiner.DeepCopy()
+		statusOnly.ResourceVersion = "3"
+		statusOnly.Status.Phase = appsv1alpha1.MinerPhaseRunning
+		condition.SetTrue(statusOnly, condition.MinerPodHealthyCondition)
+		Expect(minerChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldMiner, ObjectNew: statusOnly})).To(BeFalse())
+	})
+
+	It("should enqueue a spec update", func() {
+		oldMiner := newMiner()
+		specChanged := oldMiner.DeepCopy()
+		specChanged.Generation = 2
+		specChanged.Spec.MinerType = appsv1alpha1.MinerTypeLarge
+		Expect(minerChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldMiner, ObjectNew: specChanged})).To(BeTrue())
+	})
+
+	It("should enqueue a label or annotation update", func() {
+		oldMiner := newMiner()
+
+		labelChanged := oldMiner.DeepCopy()
+		labelChanged.Labels = map[string]string{"chain.onex.io/name": "chain"}
+		Expect(minerChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldMiner, ObjectNew: labelChanged})).To(BeTrue())
+
+		annotationChanged := oldMiner.DeepCopy()
+		annotationChanged.Annotations = map[string]string{"example.com/note": "value"}
+		Expect(minerChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldMiner, ObjectNew: annotationChanged})).To(BeTrue())
+	})
+})
+
+var _ = Describe("createPodSpec", func() {
+	reconciler := &MinerReconciler{}
+
+	newMiner := func() *appsv1alpha1.Miner {
+		return &appsv1alpha1.Miner{
+			ObjectMeta: metav1.ObjectMeta{Name: "miner", Namespace: "default"},
+			Spec: appsv1alpha1.MinerSpec{
+				ChainName: "chain",
+				MinerType: appsv1alpha1.MinerTypeSmall,
+			},
+		}
+	}
+
+	It("should apply restricted security contexts by default", func() {
+		pod := reconciler.createPodSpec(newMiner(), podAnnotationData{})
+
+		Expect(pod.Spec.SecurityContext).NotTo(BeNil())
+		Expect(*pod.Spec.SecurityContext.RunAsNonRoot).To(BeTrue())
+		Expect(pod.Spec.SecurityContext.RunAsUser).To(Equal(ptr.To(defaultRunAsUser)))
+		Expect(pod.Spec.SecurityContext.SeccompProfile.Type).To(Equal(corev1.SeccompProfileTypeRuntimeDefault))
+
+		sc := pod.Spec.Containers[0].SecurityContext
+		Expect(sc).NotTo(BeNil())
+		Expect(*sc.RunAsNonRoot).To(BeTrue())
+		Expect(sc.RunAsUser).To(Equal(ptr.To(defaultRunAsUser)))
+		Expect(*sc.AllowPrivilegeEscalation).To(BeFalse())
+		Expect(sc.Capabilities.Drop).To(ConsistOf(corev1.Capability("ALL")))
+	})
+
+	It("should apply the security contexts from the miner spec", func() {
+		miner := newMiner()
+		miner.Spec.SecurityContext = &corev1.SecurityContext{RunAsUser: ptr.To(int64(1000))}
+		miner.Spec.PodSecurityContext = &corev1.PodSecurityContext{FSGroup: ptr.To(int64(2000))}
+
+		pod := reconciler.createPodSpec(miner, podAnnotationData{})
+
+		Expect(pod.Spec.SecurityContext).To(Equal(miner.Spec.PodSecurityContext))
+		Expect(pod.Spec.Containers[0].SecurityContext).To(Equal(miner.Spec.SecurityContext))
+	})
+
+	It("should set the resources of the miner container from the miner spec", func() {
+		miner := newMiner()
+		miner.Spec.Resources = corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+		}
+
+		pod := reconciler.createPodSpec(miner, podAnnotationData{})
+
+		Expect(pod.Spec.Containers[0].Resources).To(Equal(miner.Spec.Resources))
+	})
+
+	It("should set the active deadline from the miner spec", func() {
+		miner := newMiner()
+		miner.Spec.RestartPolicy = corev1.RestartPolicyNever
+		miner.Spec.ActiveDeadlineSeconds = ptr.To(int64(3600))
+
+		pod := reconciler.createPodSpec(miner, podAnnotationData{})
+
+		Expect(pod.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+		Expect(pod.Spec.ActiveDeadlineSeconds).To(Equal(ptr.To(int64(3600))))
+	})
+
+	It("should set the priority class and runtime class from the miner spec", func() {
+		miner := newMiner()
+		miner.Spec.PriorityClassName = "miner-critical"
+		miner.Spec.RuntimeClassName = ptr.To("kata")
+		Expect(validateMinerSpec(miner)).To(Succeed())
+
+		pod := reconciler.createPodSpec(miner, podAnnotationData{})
+
+		Expect(pod.Spec.PriorityClassName).To(Equal("miner-critical"))
+		Expect(pod.Spec.RuntimeClassName).To(Equal(ptr.To("kata")))
+		// The overhead is set from the RuntimeClass on admission
+		Expect(pod.Spec.Overhead).To(BeNil())
+	})
+
+	It("should reject an invalid runtime class name", func() {
+		miner := newMiner()
+		miner.Spec.RuntimeClassName = ptr.To("Kata_Runtime")
+		Expect(validateMinerSpec(miner)).To(MatchError(ContainSubstring("invalid runtime class name")))
+	})
+
+	It("should set the DNS policy, DNS config and host aliases from the miner spec", func() {
+		miner := newMiner()
+		miner.Spec.DNSPolicy = corev1.DNSNone
+		miner.Spec.DNSConfig = &corev1.PodDNSConfig{
+			Nameservers: []string{"10.0.0.53"},
+			Searches:    []string{"miners.example.com"},
+		}
+		miner.Spec.HostAliases = []corev1.HostAlias{{IP: "10.0.0.2", Hostnames: []string{"seed-0", "seed"}}}
+		Expect(validateMinerSpec(miner)).To(Succeed())
+
+		pod := reconciler.createPodSpec(miner, podAnnotationData{})
+
+		Expect(pod.Spec.DNSPolicy).To(Equal(corev1.DNSNone))
+		Expect(pod.Spec.DNSConfig).To(Equal(miner.Spec.DNSConfig))
+		Expect(pod.Spec.HostAliases).To(Equal(miner.Spec.HostAliases))
+
+		By("Leaving the DNS settings unset by default")
+		pod = reconciler.createPodSpec(newMiner(), podAnnotationData{})
+		Expect(pod.Spec.DNSPolicy).To(BeEmpty())
+		Expect(pod.Spec.DNSConfig).To(BeNil())
+		Expect(pod.Spec.HostAliases).To(BeEmpty())
+	})
+
+	It("should require a DNS config with the None DNS policy", func() {
+		miner := newMiner()
+		miner.Spec.DNSPolicy = corev1.DNSNone
+		Expect(validateMinerSpec(miner)).To(MatchError(ContainSubstring("dns config")))
+	})
+
+	It("should reject an invalid priority class name", func() {
+		miner := newMiner()
+		miner.Spec.PriorityClassName = "Miner_Critical"
+		Expect(validateMinerSpec(miner)).To(MatchError(ContainSubstring(`"Miner_Critical"`)))
+	})
+
+	It("should truncate the pod name of a miner with a very long name", func() {
+		miner := newMiner()
+		miner.Name = strings.Repeat("long-minerset-name-", 4) + "x7k2p"
+		other := newMiner()
+		other.Name = strings.Repeat("long-minerset-name-", 4) + "q9m4z"
+
+		pod := reconciler.createPodSpec(miner, podAnnotationData{})
+
+		Expect(len(pod.Name)).To(BeNumerically("<=", maxPodNameLength))
+		Expect(validation.IsDNS1123Label(pod.Name)).To(BeEmpty())
+		Expect(validation.IsValidLabelValue(pod.Labels[minerNameLabel])).To(BeEmpty())
+		Expect(pod.Labels).To(HaveKeyWithValue(minerNameLabel, pod.Name))
+		Expect(pod.Annotations).To(HaveKeyWithValue(minerNameLabel, miner.Name))
+		Expect(minerPodName(other)).NotTo(Equal(pod.Name))
+		Expect(minerPodName(newMiner())).To(Equal("miner"))
+	})
+
+	It("should not let miner labels override the controller labels", func() {
+		miner := newMiner()
+		miner.Labels = map[string]string{
+			"app":          "notminer",
+			minerNameLabel: "other-miner",
+			chainNameLabel: "other-chain",
+			"team":         "mining",
+		}
+
+		pod := reconciler.createPodSpec(miner, podAnnotationData{})
+
+		Expect(pod.Labels).To(Equal(map[string]string{
+			"app":          "miner",
+			minerNameLabel: "miner",
+			chainNameLabel: "chain",
+			"team":         "mining",
+		}))
+	})
+
+	It("should render templated annotations and keep literal ones", func() {
+		miner := newMiner()
+		miner.Annotations = map[string]string{
+			podAnnotationPrefix + "chain":       "{{ .ChainName }}",
+			podAnnotationPrefix + "config-hash": "{{ .ConfigHash }}",
+			podAnnotationPrefix + "team":        "mining",
+		}
+
+		pod := reconciler.createPodSpec(miner, podAnnotationData{MinerName: "miner", ChainName: "chain", ConfigHash: "abc"})
+
+		Expect(pod.Annotations).To(HaveKeyWithValue(podAnnotationPrefix+"chain", "chain"))
+		Expect(pod.Annotations).To(HaveKeyWithValue(podAnnotationPrefix+"config-hash", "abc"))
+		Expect(pod.Annotations).To(HaveKeyWithValue(podAnnotationPrefix+"team", "mining"))
+		Expect(pod.Annotations).To(HaveKeyWithValue(minerNameLabel, "miner"))
+		Expect(pod.Annotations).To(HaveKeyWithValue(configChecksumAnnotation, "abc"))
+	})
+
+	It("should only propagate annotations under the pod annotation prefix", func() {
+		miner := newMiner()
+		miner.Annotations = map[string]string{
+			corev1.LastAppliedConfigAnnotation: `{"kind":"Miner"}`,
+			"example.com/description":          "Not a template: {{ .Unknown }}",
+		}
+		Expect(validateMinerSpec(miner)).To(Succeed())
+
+		pod := reconciler.createPodSpec(miner, podAnnotationData{})
+
+		Expect(pod.Annotations).NotTo(HaveKey(corev1.LastAppliedConfigAnnotation))
+		Expect(pod.Annotations).NotTo(HaveKey("example.com/description"))
+	})
+
+	It("should use the command from the command annotation", func() {
+		miner := newMiner()
+		miner.Annotations = map[string]string{commandAnnotation: `sh -c "echo 'mining' && sleep 60"`}
+		Expect(validateMinerSpec(miner)).To(Succeed())
+
+		pod := reconciler.createPodSpec(miner, podAnnotationData{})
+
+		Expect(pod.Spec.Containers[0].Command).To(Equal([]string{"sh", "-c", "echo 'mining' && sleep 60"}))
+	})
+
+	It("should reject command annotations that cannot be parsed", func() {
+		miner := newMiner()
+		for _, command := range []string{"", "   ", `sh -c "sleep 60`, `sleep 60\`} {
+			miner.Annotations = map[string]string{commandAnnotation: command}
+			Expect(validateMinerSpec(miner)).To(MatchError(ContainSubstring(commandAnnotation)), command)
+		}
+	})
+
+	It("should reject annotation templates that cannot be rendered", func() {
+		miner := newMiner()
+		miner.Annotations = map[string]string{podAnnotationPrefix + "chain": "{{ .Chain"}
+		Expect(validateMinerSpec(miner)).To(MatchError(ContainSubstring(podAnnotationPrefix + "chain")))
+
+		miner.Annotations = map[string]string{podAnnotationPrefix + "chain": "{{ .Unknown }}"}
+		Expect(validateMinerSpec(miner)).To(MatchError(ContainSubstring(podAnnotationPrefix + "chain")))
+	})
+
+	It("should reject sidecars colliding with the miner container", func() {
+		miner := newMiner()
+		miner.Spec.Sidecars = []corev1.Container{{Name: "miner", Image: "exporter:latest"}}
+		Expect(validateMinerSpec(miner)).To(MatchError(ContainSubstring(`"miner"`)))
+
+		miner.Spec.Sidecars = []corev1.Container{{Name: chainConfigVolumeName, Image: "exporter:latest"}}
+		Expect(validateMinerSpec(miner)).To(MatchError(ContainSubstring(fmt.Sprintf("sidecar container name %q", chainConfigVolumeName))))
+
+		miner.Spec.Sidecars = []corev1.Container{
+			{Name: "exporter", Image: "exporter:latest"},
+			{Name: "exporter", Image: "exporter:latest"},
+		}
+		Expect(validateMinerSpec(miner)).To(MatchError(ContainSubstring(`"exporter"`)))
+
+		miner.Spec.Sidecars = miner.Spec.Sidecars[:1]
+		Expect(validateMinerSpec(miner)).To(Succeed())
+	})
+
+	It("should reject init containers colliding with other containers of the pod", func() {
+		miner := newMiner()
+		miner.Spec.Sidecars = []corev1.Container{{Name: "exporter", Image: "exporter:latest"}}
+		for _, name := range []string{"miner", "exporter", chainConfigVolumeName} {
+			miner.Spec.InitContainers = []corev1.Container{{Name: name, Image: "busybox"}}
+			Expect(validateMinerSpec(miner)).To(MatchError(ContainSubstring(fmt.Sprintf("init container name %q", name))), name)
+		}
+
+		miner.Spec.InitContainers = []corev1.Container{
+			{Name: "download", Image: "busybox"},
+			{Name: "download", Image: "busybox"},
+		}
+		Expect(validateMinerSpec(miner)).To(MatchError(ContainSubstring(`"download"`)))
+
+		miner.Spec.InitContainers = miner.Spec.InitContainers[:1]
+		Expect(validateMinerSpec(miner)).To(Succeed())
+	})
+
+	It("should validate the miner container name", func() {
+		miner := newMiner()
+		miner.Spec.ContainerName = "Miner_1"
+		Expect(validateMinerSpec(miner)).To(MatchError(ContainSubstring(`"Miner_1"`)))
+
+		miner.Spec.ContainerName = "worker"
+		miner.Spec.Sidecars = []corev1.Container{{Name: "worker", Image: "exporter:latest"}}
+		Expect(validateMinerSpec(miner)).To(MatchError(ContainSubstring(`"worker"`)))
+
+		miner.Spec.Sidecars = []corev1.Container{{Name: "miner", Image: "exporter:latest"}}
+		Expect(validateMinerSpec(miner)).To(Succeed())
+	})
+})
+
+var _ = Describe("isMinerReady", func() {
+	DescribeTable("combinations of phase and PodHealthy condition",
+		func(phase appsv1alpha1.MinerPhase, podHealthy *bool, expected bool) {
+			miner := &appsv1alpha1.Miner{Status: appsv1alpha1.MinerStatus{Phase: phase}}
+			if podHealthy != nil {
+				if *podHealthy {
+					condition.SetTrue(miner, condition.MinerPodHealthyCondition)
+				} else {
+					condition.SetFalse(miner, condition.MinerPodHealthyCondition, condition.ProvisioningReason, "Pod is not ready yet")
+				}
+			}
+			Expect(isMinerReady(miner)).To(Equal(expected))
+		},
+		Entry("running and healthy", appsv1alpha1.MinerPhaseRunning, ptr.To(true), true),
+		Entry("running and unhealthy", appsv1alpha1.MinerPhaseRunning, ptr.To(false), false),
+		Entry("running without condition", appsv1alpha1.MinerPhaseRunning, nil, false),
+		Entry("provisioning and healthy", appsv1alpha1.MinerPhaseProvisioning, ptr.To(true), false),
+		Entry("pending and unhealthy", appsv1alpha1.MinerPhasePending, ptr.To(false), false),
+		Entry("failed and healthy", appsv1alpha1.MinerPhaseFailed, ptr.To(true), false),
+	)
+})
+
+var _ = Describe("classifyPodFailure", func() {
+	waitingPod := func(reason string) *corev1.Pod {
+		return &corev1.Pod{
+			Status: corev1.PodStatus{
+				Phase: corev1.PodFailed,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "miner",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason, Message: "waiting"}},
+				}},
+			},
+		}
+	}
+
+	DescribeTable("should map pod statuses to failure categories",
+		func(pod *corev1.Pod, expected appsv1alpha1.MinerFailureReason) {
+			reason, message := classifyPodFailure(pod)
+			Expect(reason).To(Equal(expected))
+			Expect(message).NotTo(BeEmpty())
+		},
+		Entry("ImagePullBackOff", waitingPod("ImagePullBackOff"), appsv1alpha1.MinerFailureReasonImagePullError),
+		Entry("ErrImagePull", waitingPod("ErrImagePull"), appsv1alpha1.MinerFailureReasonImagePullError),
+		Entry("CrashLoopBackOff", waitingPod("CrashLoopBackOff"), appsv1alpha1.MinerFailureReasonCrashLoop),
+		Entry("OOMKilled", &corev1.Pod{
+			Status: corev1.PodStatus{
+				Phase: corev1.PodFailed,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "miner",
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+				}},
+			},
+		}, appsv1alpha1.MinerFailureReasonOOMKilled),
+		Entry("Unknown", &corev1.Pod{
+			Status: corev1.PodStatus{Phase: corev1.PodFailed, Message: "evicted"},
+		}, appsv1alpha1.MinerFailureReasonUnknown),
+	)
+})
+
+// podCreateFailingClient is a client that always fails to create pods.
+type podCreateFailingClient struct {
+	client.Client
+}
+
+func (c *podCreateFailingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if _, ok := obj.(*corev1.Pod); ok {
+		return fmt.Errorf("injected pod creation failure")
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+// statusWriteCountingClient is a client that counts writes to the status subresource.
+type statusWriteCountingClient struct {
+	client.Client
+	statusWrites int
+}
+
+func (c *statusWriteCountingClient) Status() client.SubResourceWriter {
+	return &countingStatusWriter{SubResourceWriter: c.Client.Status(), client: c}
+}
+
+type countingStatusWriter struct {
+	client.SubResourceWriter
+	client *statusWriteCountingClient
+}
+
+func (w *countingStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	w.client.statusWrites++
+	return w.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+func (w *countingStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	w.client.statusWrites++
+	return w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+}
+
+// minerTypeOverrideClient is a client that reports a fixed MinerType for every miner it
+// reads. The CRD schema rejects unknown miner types, so they cannot be stored directly.
+type minerTypeOverrideClient struct {
+	client.Client
+	minerType appsv1alpha1.MinerType
+}
+
+func (c *minerTypeOverrideClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if err := c.Client.Get(ctx, key, obj, opts...); err != nil {
+		return err
+	}
+	if miner, ok := obj.(*appsv1alpha1.Miner); ok {
+		miner.Spec.MinerType = c.minerType
+	}
+	return nil
+}
+
+// stalePodClient is a client whose first pod lookup reports the pod as not found,
+// simulating a cache that has not observed a pod created concurrently.
+type stalePodClient struct {
+	client.Client
+	observed bool
+}
+
+func (c *stalePodClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if _, ok := obj.(*corev1.Pod); ok && !c.observed {
+		c.observed = true
+		return errors.NewNotFound(corev1.Resource("pods"), key.Name)
+	}
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+// histogramSampleCount returns the number of observations recorded by the histogram.
+func histogramSampleCount(h prometheus.Histogram) uint64 {
+	m := &dto.Metric{}
+	Expect(h.Write(m)).To(Succeed())
+	return m.GetHistogram().GetSampleCount()
+}
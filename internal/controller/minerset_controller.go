@@ -19,18 +19,28 @@ package controller
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	appsv1alpha1 "github.com/ashwinyue/minerx/api/v1alpha1"
 	"github.com/ashwinyue/minerx/pkg/condition"
@@ -41,18 +51,63 @@ const (
 	minerSetNameLabel = "minerset.onex.io/name"
 	chainNameLabel    = "chain.onex.io/name"
 
+	// minerProtectAnnotation, set to "true" on a Miner, makes a scale down of its MinerSet
+	// delete the miner only once no unprotected miners are left to delete.
+	minerProtectAnnotation = "minerset.onex.io/protect"
+
 	stateConfirmationTimeout  = 10 * time.Second
 	stateConfirmationInterval = 100 * time.Millisecond
+
+	chainReadyRequeueInterval = 5 * time.Second
+
+	// invalidTemplateRequeueInterval is how long to wait before retrying after the API
+	// server rejected a miner built from the template. A template change triggers a
+	// reconcile on its own, so this is only a slow safety net.
+	invalidTemplateRequeueInterval = 5 * time.Minute
+
+	// scaleDownBlockedRequeueInterval is how long to wait before retrying a scale down
+	// that a PodDisruptionBudget refused. Budgets free up as miners become ready again.
+	scaleDownBlockedRequeueInterval = 30 * time.Second
+
+	// minerSetResyncInterval is how often a MinerSet is reconciled when scaling asks for no
+	// earlier requeue, so that the readiness of its miners is kept up to date.
+	minerSetResyncInterval = 15 * time.Second
+
+	// maxUnavailableMinersReported caps the number of miners listed in status.unavailableMiners.
+	maxUnavailableMinersReported = 10
+
+	// minerControllerUIDField indexes miners by the UID of their controller, with miners
+	// without a controller indexed under the empty string.
+	minerControllerUIDField = ".metadata.controller.uid"
 )
 
 var (
 	msKind = appsv1alpha1.GroupVersion.WithKind("MinerSet")
+
+	// minerSetChangedPredicate ignores updates of a MinerSet that leave its generation,
+	// labels and annotations unchanged, such as the status writes of the controller itself.
+	minerSetChangedPredicate = predicate.Or(
+		predicate.GenerationChangedPredicate{},
+		predicate.LabelChangedPredicate{},
+		predicate.AnnotationChangedPredicate{},
+	)
+
+	// reservedMinerMetadataKeys are the label and annotation keys the controller sets on
+	// the miners of a MinerSet. They are dropped from the template labels and annotations.
+	reservedMinerMetadataKeys = sets.New(minerSetNameLabel, chainNameLabel, templateHashAnnotation)
 )
 
 // MinerSetReconciler reconciles a MinerSet object
 type MinerSetReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// DisableFinalizers stops the controller from adding its finalizer, see --disable-finalizers.
+	DisableFinalizers bool
+
+	// indexedByController is set once the miner controller UID index is registered with
+	// the manager's cache, so miners are listed by owner rather than by label only.
+	indexedByController bool
 }
 
 // +kubebuilder:rbac:groups=apps.onex.io,resources=minersets,verbs=get;list;watch;create;update;patch;delete
@@ -60,10 +115,17 @@ type MinerSetReconciler struct {
 // +kubebuilder:rbac:groups=apps.onex.io,resources=minersets/finalizers,verbs=update
 // +kubebuilder:rbac:groups=apps.onex.io,resources=miners,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.onex.io,resources=miners/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps.onex.io,resources=chains,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
-func (r *MinerSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *MinerSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	defer recoverReconcilePanic(ctx, "minerset", req, &result, &err)
+
 	log := log.FromContext(ctx)
 
 	ms := &appsv1alpha1.MinerSet{}
@@ -89,8 +151,9 @@ func (r *MinerSetReconciler) reconcileDelete(ctx context.Context, ms *appsv1alph
 	log := log.FromContext(ctx)
 
 	if controllerutil.ContainsFinalizer(ms, minerSetFinalizer) {
+		original := ms.DeepCopy()
 		controllerutil.RemoveFinalizer(ms, minerSetFinalizer)
-		if err := r.Update(ctx, ms); err != nil {
+		if err := r.patchMinerSet(ctx, original, ms); err != nil {
 			log.Error(err, "Failed to remove finalizer from MinerSet")
 			return ctrl.Result{}, err
 		}
@@ -103,44 +166,101 @@ func (r *MinerSetReconciler) reconcileDelete(ctx context.Context, ms *appsv1alph
 func (r *MinerSetReconciler) reconcile(ctx context.Context, ms *appsv1alpha1.MinerSet) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 
-	if !controllerutil.ContainsFinalizer(ms, minerSetFinalizer) {
+	if !r.DisableFinalizers && !controllerutil.ContainsFinalizer(ms, minerSetFinalizer) {
+		original := ms.DeepCopy()
 		controllerutil.AddFinalizer(ms, minerSetFinalizer)
-		if err := r.Update(ctx, ms); err != nil {
+		if err := r.patchMinerSet(ctx, original, ms); err != nil {
 			log.Error(err, "Failed to add finalizer to MinerSet")
 			return ctrl.Result{}, err
 		}
 	}
 
-	// Set chain name label
-	if ms.Labels == nil {
-		ms.Labels = make(map[string]string)
+	// Label the MinerSet with its chain, patching only when the label is missing or stale
+	if chainName := ms.Spec.Template.Spec.ChainName; chainName != "" && ms.Labels[chainNameLabel] != chainName {
+		original := ms.DeepCopy()
+		if ms.Labels == nil {
+			ms.Labels = make(map[string]string)
+		}
+		ms.Labels[chainNameLabel] = chainName
+		if err := r.patchMinerSet(ctx, original, ms); err != nil {
+			log.Error(err, "Failed to set chain label on MinerSet")
+			return ctrl.Result{}, err
+		}
 	}
-	ms.Labels[chainNameLabel] = ms.Spec.Template.Spec.ChainName
 
-	// Convert selector to map
-	selectorMap, err := metav1.LabelSelectorAsMap(&ms.Spec.Selector)
+	observed := ms.Status.DeepCopy()
+
+	// Miners cannot be created in a terminating namespace, which deletes the MinerSet anyway
+	terminating, err := r.isNamespaceTerminating(ctx, ms.Namespace)
 	if err != nil {
-		log.Error(err, "Failed to convert MinerSet label selector to a map")
+		log.Error(err, "Failed to get Namespace", "namespace", ms.Namespace)
 		return ctrl.Result{}, err
 	}
+	if terminating {
+		log.Info("Namespace is terminating, skipping miner reconciliation", "namespace", ms.Namespace)
+		condition.MarkFalsef(ms, condition.MinersCreatedCondition, condition.NamespaceTerminatingReason,
+			"Namespace %q is terminating", ms.Namespace)
+		if !equality.Semantic.DeepEqual(observed, &ms.Status) {
+			if err := updateStatusWithRetry(ctx, r.Client, ms); err != nil {
+				log.Error(err, "Failed to update MinerSet status")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Convert the selector, including any set-based requirements
+	selector, err := metav1.LabelSelectorAsSelector(&ms.Spec.Selector)
+	if err != nil {
+		// Retrying cannot fix the selector, a spec update triggers the next reconcile
+		log.Error(err, "Failed to convert MinerSet label selector")
+		condition.MarkFalsef(ms, condition.MinersCreatedCondition, condition.SelectorInvalidReason, "Invalid selector: %v", err)
+		if !equality.Semantic.DeepEqual(observed, &ms.Status) {
+			if err := updateStatusWithRetry(ctx, r.Client, ms); err != nil {
+				log.Error(err, "Failed to update MinerSet status")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	templateHash, err := TemplateHash(ms.Spec.Template)
+	if err != nil {
+		// Retrying cannot fix the template, a spec update triggers the next reconcile
+		log.Error(err, "Failed to hash the miner template")
+		condition.MarkFalsef(ms, condition.MinersCreatedCondition, condition.InvalidConfigurationReason, "Invalid miner template: %v", err)
+		if !equality.Semantic.DeepEqual(observed, &ms.Status) {
+			if err := updateStatusWithRetry(ctx, r.Client, ms); err != nil {
+				log.Error(err, "Failed to update MinerSet status")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
 
 	// List all Miners managed by this MinerSet
-	allMiners := &appsv1alpha1.MinerList{}
-	if err := r.List(ctx, allMiners, client.InNamespace(ms.Namespace), client.MatchingLabels(selectorMap)); err != nil {
+	allMiners, err := r.listMiners(ctx, ms, selector)
+	if err != nil {
 		log.Error(err, "Failed to list miners")
 		return ctrl.Result{}, err
 	}
 
 	// Filter Miners: exclude those controlled by others, adopt orphans
-	filteredMiners := make([]*appsv1alpha1.Miner, 0, len(allMiners.Items))
-	for idx := range allMiners.Items {
-		miner := &allMiners.Items[idx]
+	filteredMiners := make([]*appsv1alpha1.Miner, 0, len(allMiners))
+	for _, miner := range allMiners {
 		if shouldExcludeMiner(ms, miner) {
 			continue
 		}
 
-		// Adopt orphaned miners
+		// Adopt orphaned miners, unless adoption is disabled or they belong to another chain
 		if metav1.GetControllerOf(miner) == nil {
+			if !adoptsOrphans(ms) {
+				continue
+			}
+			if !belongsToChain(ms, miner) {
+				log.Info("Refusing to adopt Miner of another chain", "miner", miner.Name, "chain", miner.Spec.ChainName)
+				continue
+			}
 			if err := r.adoptOrphan(ctx, ms, miner); err != nil {
 				log.Error(err, "Failed to adopt Miner", "miner", miner.Name)
 				continue
@@ -148,67 +268,369 @@ func (r *MinerSetReconciler) reconcile(ctx context.Context, ms *appsv1alpha1.Min
 			log.Info("Adopted Miner", "miner", miner.Name)
 		}
 
+		// Terminating miners are on their way out and must not count as replicas,
+		// otherwise a scale down keeps deleting and a scale up is blocked
+		if !miner.DeletionTimestamp.IsZero() {
+			continue
+		}
+
 		filteredMiners = append(filteredMiners, miner)
 	}
 
+	// Wait for the chain's ConfigMap before creating miners
+	if ms.Spec.WaitForChainReady {
+		ready, err := r.isChainReady(ctx, ms)
+		if err != nil {
+			log.Error(err, "Failed to get Chain", "chain", ms.Spec.Template.Spec.ChainName)
+			return ctrl.Result{}, err
+		}
+		if !ready {
+			log.Info("Waiting for Chain ConfigMap to be created", "chain", ms.Spec.Template.Spec.ChainName)
+			condition.MarkFalsef(ms, condition.ChainReadyCondition, condition.WaitingForChainReason,
+				"Waiting for Chain %q to create its ConfigMap", ms.Spec.Template.Spec.ChainName)
+			if err := r.updateStatus(ctx, ms, observed, filteredMiners, templateHash); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: chainReadyRequeueInterval}, nil
+		}
+		condition.SetTrue(ms, condition.ChainReadyCondition)
+	} else {
+		// Clear a stale condition left behind when waiting was turned off
+		condition.Delete(ms, condition.ChainReadyCondition)
+	}
+
+	if err := r.reconcileService(ctx, ms); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcilePodDisruptionBudget(ctx, ms); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Sync replicas
-	result, err := r.syncReplicas(ctx, ms, filteredMiners)
+	result, err := r.syncReplicas(ctx, ms, filteredMiners, templateHash)
 	if err != nil {
 		return result, err
 	}
 
 	// Update status
-	if err := r.updateStatus(ctx, ms, filteredMiners); err != nil {
+	if err := r.updateStatus(ctx, ms, observed, filteredMiners, templateHash); err != nil {
 		return ctrl.Result{}, err
 	}
 
 	log.Info("MinerSet reconciled successfully")
-	return result, nil
+	return resyncResult(result, minerSetResyncInterval), nil
 }
 
-// syncReplicas scales Miner resources up or down
-func (r *MinerSetReconciler) syncReplicas(ctx context.Context, ms *appsv1alpha1.MinerSet, miners []*appsv1alpha1.Miner) (ctrl.Result, error) {
+// isNamespaceTerminating returns true if the namespace is being deleted.
+func (r *MinerSetReconciler) isNamespaceTerminating(ctx context.Context, name string) (bool, error) {
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, client.ObjectKey{Name: name}, namespace); err != nil {
+		return false, err
+	}
+	return !namespace.DeletionTimestamp.IsZero() || namespace.Status.Phase == corev1.NamespaceTerminating, nil
+}
+
+// listMiners returns the miners in the namespace of the MinerSet that match its selector.
+// With the controller UID index, only the miners it controls and, when it adopts orphans,
+// the miners without a controller are listed, instead of every miner matching the labels.
+func (r *MinerSetReconciler) listMiners(ctx context.Context, ms *appsv1alpha1.MinerSet, selector labels.Selector) ([]*appsv1alpha1.Miner, error) {
+	if !r.indexedByController {
+		minerList := &appsv1alpha1.MinerList{}
+		if err := r.List(ctx, minerList, client.InNamespace(ms.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, err
+		}
+		miners := make([]*appsv1alpha1.Miner, 0, len(minerList.Items))
+		for idx := range minerList.Items {
+			miners = append(miners, &minerList.Items[idx])
+		}
+		return miners, nil
+	}
+
+	controllerUIDs := []string{string(ms.UID)}
+	if adoptsOrphans(ms) {
+		controllerUIDs = append(controllerUIDs, "")
+	}
+
+	var miners []*appsv1alpha1.Miner
+	for _, uid := range controllerUIDs {
+		minerList := &appsv1alpha1.MinerList{}
+		if err := r.List(ctx, minerList, client.InNamespace(ms.Namespace), client.MatchingFields{minerControllerUIDField: uid}); err != nil {
+			return nil, err
+		}
+		for idx := range minerList.Items {
+			if selector.Matches(labels.Set(minerList.Items[idx].Labels)) {
+				miners = append(miners, &minerList.Items[idx])
+			}
+		}
+	}
+	return miners, nil
+}
+
+// indexMinerByControllerUID returns the UID of the controller of a miner for the
+// minerControllerUIDField index.
+func indexMinerByControllerUID(obj client.Object) []string {
+	owner := metav1.GetControllerOf(obj)
+	if owner == nil {
+		return []string{""}
+	}
+	return []string{string(owner.UID)}
+}
+
+// reconcileService creates or updates the headless Service of the MinerSet's miners, and
+// deletes it when the MinerSet no longer asks for one.
+func (r *MinerSetReconciler) reconcileService(ctx context.Context, ms *appsv1alpha1.MinerSet) error {
+	log := log.FromContext(ctx)
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ms.Name,
+			Namespace: ms.Namespace,
+		},
+	}
+
+	if !ms.Spec.HeadlessService {
+		if ms.Status.ServiceRef == nil {
+			return nil
+		}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(svc), svc); err != nil && !errors.IsNotFound(err) {
+			return err
+		} else if err == nil && metav1.IsControlledBy(svc, ms) {
+			if err := r.Delete(ctx, svc); err != nil && !errors.IsNotFound(err) {
+				log.Error(err, "Failed to delete headless Service", "service", svc.Name)
+				return err
+			}
+			log.Info("Deleted headless Service", "service", svc.Name)
+		}
+		ms.Status.ServiceRef = nil
+		return nil
+	}
+
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, svc, func() error {
+		if !svc.CreationTimestamp.IsZero() && !metav1.IsControlledBy(svc, ms) {
+			return fmt.Errorf("Service %q exists and is not controlled by the MinerSet", svc.Name)
+		}
+		if svc.Labels == nil {
+			svc.Labels = make(map[string]string)
+		}
+		svc.Labels[minerSetNameLabel] = ms.Name
+		// The cluster IP is immutable, a Service that exists already keeps its own
+		if svc.CreationTimestamp.IsZero() {
+			svc.Spec.ClusterIP = corev1.ClusterIPNone
+		}
+		svc.Spec.Selector = map[string]string{minerSetNameLabel: ms.Name}
+		// Peers must be resolvable while they are starting up
+		svc.Spec.PublishNotReadyAddresses = true
+		return controllerutil.SetControllerReference(ms, svc, r.Scheme)
+	})
+	if err != nil {
+		log.Error(err, "Failed to reconcile headless Service", "service", svc.Name)
+		return err
+	}
+	if op != controllerutil.OperationResultNone {
+		log.Info("Reconciled headless Service", "service", svc.Name, "operation", op)
+	}
+
+	ms.Status.ServiceRef = &appsv1alpha1.LocalObjectReference{Name: svc.Name}
+	return nil
+}
+
+// reconcilePodDisruptionBudget creates or updates the PodDisruptionBudget of the MinerSet's
+// miners, and deletes it when the MinerSet no longer asks for one.
+func (r *MinerSetReconciler) reconcilePodDisruptionBudget(ctx context.Context, ms *appsv1alpha1.MinerSet) error {
 	log := log.FromContext(ctx)
 
-	if ms.Spec.Replicas == nil {
-		return ctrl.Result{}, fmt.Errorf("Replicas field in MinerSet spec is nil")
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ms.Name,
+			Namespace: ms.Namespace,
+		},
+	}
+
+	if ms.Spec.PodDisruptionBudget == nil {
+		if err := r.Get(ctx, client.ObjectKeyFromObject(pdb), pdb); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if !metav1.IsControlledBy(pdb, ms) {
+			return nil
+		}
+		if err := r.Delete(ctx, pdb); err != nil && !errors.IsNotFound(err) {
+			log.Error(err, "Failed to delete PodDisruptionBudget", "podDisruptionBudget", pdb.Name)
+			return err
+		}
+		log.Info("Deleted PodDisruptionBudget", "podDisruptionBudget", pdb.Name)
+		return nil
+	}
+
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, pdb, func() error {
+		if !pdb.CreationTimestamp.IsZero() && !metav1.IsControlledBy(pdb, ms) {
+			return fmt.Errorf("PodDisruptionBudget %q exists and is not controlled by the MinerSet", pdb.Name)
+		}
+		if pdb.Labels == nil {
+			pdb.Labels = make(map[string]string)
+		}
+		pdb.Labels[minerSetNameLabel] = ms.Name
+		pdb.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{minerSetNameLabel: ms.Name}}
+		budget := ms.Spec.PodDisruptionBudget.DeepCopy()
+		pdb.Spec.MinAvailable = budget.MinAvailable
+		pdb.Spec.MaxUnavailable = budget.MaxUnavailable
+
+		// An absolute minimum above the replicas would block every eviction
+		replicas := desiredReplicas(ms)
+		if pdb.Spec.MinAvailable != nil && pdb.Spec.MinAvailable.Type == intstr.Int && pdb.Spec.MinAvailable.IntVal > replicas {
+			pdb.Spec.MinAvailable = ptr.To(intstr.FromInt32(replicas))
+		}
+		return controllerutil.SetControllerReference(ms, pdb, r.Scheme)
+	})
+	if err != nil {
+		log.Error(err, "Failed to reconcile PodDisruptionBudget", "podDisruptionBudget", pdb.Name)
+		return err
+	}
+	if op != controllerutil.OperationResultNone {
+		log.Info("Reconciled PodDisruptionBudget", "podDisruptionBudget", pdb.Name, "operation", op)
+	}
+
+	return nil
+}
+
+// desiredReplicas returns the number of miners the MinerSet asks for. The replicas may be
+// owned by an autoscaler writing to the scale subresource, so they are always read from
+// the MinerSet fetched for the current reconcile and never cached across reconciles.
+func desiredReplicas(ms *appsv1alpha1.MinerSet) int32 {
+	// Replicas is only unset when the defaulting webhook is disabled
+	return ptr.Deref(ms.Spec.Replicas, appsv1alpha1.DefaultMinerSetReplicas)
+}
+
+// patchMinerSet sends the changes made to ms since original as a merge patch, so that
+// fields the controller did not touch are never written back from a stale copy. The
+// replicas belong to the user or an autoscaler, so a patch changing them is refused.
+func (r *MinerSetReconciler) patchMinerSet(ctx context.Context, original, ms *appsv1alpha1.MinerSet) error {
+	if !ptr.Equal(original.Spec.Replicas, ms.Spec.Replicas) {
+		return fmt.Errorf("refusing to change the replicas of MinerSet %s/%s", ms.Namespace, ms.Name)
 	}
+	return r.Patch(ctx, ms, client.MergeFrom(original))
+}
+
+// syncReplicas scales Miner resources up or down
+func (r *MinerSetReconciler) syncReplicas(ctx context.Context, ms *appsv1alpha1.MinerSet, miners []*appsv1alpha1.Miner, templateHash string) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	replicas := desiredReplicas(ms)
 
-	diff := len(miners) - int(*ms.Spec.Replicas)
+	diff := len(miners) - int(replicas)
 	switch {
 	case diff < 0:
 		// Scale up
 		diff *= -1
-		log.Info("Scaling up MinerSet", "replicas", *ms.Spec.Replicas, "current", len(miners))
-		if err := r.createMiners(ctx, ms, diff); err != nil {
+		if ms.Spec.MaxProvisioning != nil {
+			allowed := max(int(*ms.Spec.MaxProvisioning)-provisioningMiners(miners), 0)
+			if allowed < diff {
+				log.Info("Throttling miner creation", "maxProvisioning", *ms.Spec.MaxProvisioning, "allowed", allowed, "missing", diff)
+				diff = allowed
+			}
+		}
+		log.Info("Scaling up MinerSet", "replicas", replicas, "current", len(miners))
+		if err := r.createMiners(ctx, ms, miners, diff, templateHash); err != nil {
+			if errors.IsInvalid(err) {
+				log.Error(err, "Miner template rejected by the API server")
+				condition.SetFalse(ms, condition.MinersCreatedCondition, condition.InvalidConfigurationReason, err.Error())
+				return ctrl.Result{RequeueAfter: invalidTemplateRequeueInterval}, nil
+			}
 			return ctrl.Result{}, err
 		}
 		condition.SetTrue(ms, condition.MinersCreatedCondition)
 		condition.SetFalse(ms, condition.ResizedCondition, condition.CreatingReason, "Creating miners")
 	case diff > 0:
 		// Scale down
-		log.Info("Scaling down MinerSet", "replicas", *ms.Spec.Replicas, "current", len(miners), "deletePolicy", ms.Spec.DeletePolicy)
+		log.Info("Scaling down MinerSet", "replicas", replicas, "current", len(miners), "deletePolicy", ms.Spec.DeletePolicy)
 		minersToDelete := r.getMinersToDelete(ms, miners, diff)
+		allowed, err := r.disruptionsAllowed(ctx, ms, miners)
+		if err != nil {
+			log.Error(err, "Failed to get the PodDisruptionBudget")
+			return ctrl.Result{}, err
+		}
+		minersToDelete, heldBack := limitDisruptions(minersToDelete, allowed)
 		if err := r.deleteMiners(ctx, minersToDelete); err != nil {
 			return ctrl.Result{}, err
 		}
+		if heldBack > 0 {
+			log.Info("Scale down blocked by the PodDisruptionBudget", "disruptionsAllowed", allowed, "heldBack", heldBack)
+			condition.MarkFalsef(ms, condition.ResizedCondition, condition.DisruptionBudgetBlockedReason,
+				"Scale down is blocked by the PodDisruptionBudget %s: %d ready miners cannot be deleted yet", ms.Name, heldBack)
+			return ctrl.Result{RequeueAfter: scaleDownBlockedRequeueInterval}, nil
+		}
 		condition.SetTrue(ms, condition.MinersCreatedCondition)
 		condition.SetFalse(ms, condition.ResizedCondition, condition.DeletingReason, "Deleting miners")
 	default:
-		// Replicas match desired count
+		// Replicas match desired count, the resize completes once all miners are Running.
+		// Miners becoming unready afterwards are reported by MinersReady instead.
 		condition.SetTrue(ms, condition.MinersCreatedCondition)
-		condition.SetTrue(ms, condition.ResizedCondition)
+		if pending := notRunningMiners(miners); pending > 0 && !condition.IsTrue(ms, condition.ResizedCondition) {
+			condition.MarkFalsef(ms, condition.ResizedCondition, condition.ProvisioningReason,
+				"Waiting for %d of %d miners to be running", pending, len(miners))
+		} else {
+			condition.SetTrue(ms, condition.ResizedCondition)
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// provisioningMiners returns the number of miners that have not reached the Running,
+// Succeeded or Failed phase yet, including new miners without a phase.
+func provisioningMiners(miners []*appsv1alpha1.Miner) int {
+	count := 0
+	for _, miner := range miners {
+		switch miner.Status.Phase {
+		case "", appsv1alpha1.MinerPhasePending, appsv1alpha1.MinerPhaseProvisioning:
+			count++
+		}
 	}
+	return count
+}
 
-	return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+// notRunningMiners returns the number of miners that are not in the Running phase.
+func notRunningMiners(miners []*appsv1alpha1.Miner) int {
+	count := 0
+	for _, miner := range miners {
+		if miner.Status.Phase != appsv1alpha1.MinerPhaseRunning {
+			count++
+		}
+	}
+	return count
 }
 
-func (r *MinerSetReconciler) createMiners(ctx context.Context, ms *appsv1alpha1.MinerSet, count int) error {
-	for i := 0; i < count; i++ {
-		miner := r.computeDesiredMiner(ms, nil)
+// createMiners creates count miners for the MinerSet, stamped with the given template hash.
+// With the Ordinal naming strategy, miners take the lowest ordinals not used by the
+// existing miners.
+func (r *MinerSetReconciler) createMiners(ctx context.Context, ms *appsv1alpha1.MinerSet, miners []*appsv1alpha1.Miner, count int, templateHash string) error {
+	used := sets.New[string]()
+	for _, miner := range miners {
+		used.Insert(miner.Name)
+	}
+
+	ordinalNames := ms.Spec.NamingStrategy == appsv1alpha1.MinerNamingStrategyOrdinal
+	ordinal := 0
+	for created := 0; created < count; {
+		miner := r.computeDesiredMiner(ms, nil, templateHash)
+		if ordinalNames {
+			for used.Has(ordinalMinerName(ms, ordinal)) {
+				ordinal++
+			}
+			miner.GenerateName = ""
+			miner.Name = ordinalMinerName(ms, ordinal)
+			used.Insert(miner.Name)
+		}
+
 		if err := r.Create(ctx, miner); err != nil {
+			if ordinalNames && errors.IsAlreadyExists(err) {
+				// A terminating miner still holds the name, try the next ordinal
+				log.FromContext(ctx).Info("Miner name is still in use, skipping it", "miner", miner.Name)
+				continue
+			}
 			return fmt.Errorf("failed to create miner %q: %w", miner.Name, err)
 		}
+		created++
 
 		if err := r.waitForMinerCreation(ctx, miner); err != nil {
 			return fmt.Errorf("failed waiting for miner %q creation: %w", miner.Name, err)
@@ -217,8 +639,22 @@ func (r *MinerSetReconciler) createMiners(ctx context.Context, ms *appsv1alpha1.
 	return nil
 }
 
+// ordinalMinerName returns the name of the miner of the MinerSet with the given ordinal.
+func ordinalMinerName(ms *appsv1alpha1.MinerSet, ordinal int) string {
+	return fmt.Sprintf("%s-%d", ms.Name, ordinal)
+}
+
+// deleteMiners deletes the given miners. The MinerSet finalizer is released first, so that
+// a scale down, including to zero, is not held up by the MinerSet itself.
 func (r *MinerSetReconciler) deleteMiners(ctx context.Context, miners []*appsv1alpha1.Miner) error {
 	for _, miner := range miners {
+		if controllerutil.ContainsFinalizer(miner, minerSetFinalizer) {
+			patch := client.MergeFrom(miner.DeepCopy())
+			controllerutil.RemoveFinalizer(miner, minerSetFinalizer)
+			if err := r.Patch(ctx, miner, patch); err != nil && !errors.IsNotFound(err) {
+				return fmt.Errorf("failed to remove finalizer from miner %q: %w", miner.Name, err)
+			}
+		}
 		if !miner.DeletionTimestamp.IsZero() {
 			continue
 		}
@@ -229,18 +665,108 @@ func (r *MinerSetReconciler) deleteMiners(ctx context.Context, miners []*appsv1a
 	return nil
 }
 
-func (r *MinerSetReconciler) computeDesiredMiner(ms *appsv1alpha1.MinerSet, existingMiner *appsv1alpha1.Miner) *appsv1alpha1.Miner {
-	minerLabels := make(map[string]string)
+// disruptionsAllowed returns how many ready miners of the MinerSet can be deleted without
+// violating its PodDisruptionBudget, or -1 if the MinerSet has no budget. Miners are
+// deleted rather than evicted, so the budget is enforced here instead of by the API server.
+// Like the Eviction API, a budget whose status is not up to date allows no disruption.
+func (r *MinerSetReconciler) disruptionsAllowed(ctx context.Context, ms *appsv1alpha1.MinerSet, miners []*appsv1alpha1.Miner) (int, error) {
+	if ms.Spec.PodDisruptionBudget == nil {
+		return -1, nil
+	}
+
+	pdb := &policyv1.PodDisruptionBudget{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: ms.Namespace, Name: ms.Name}, pdb); err != nil {
+		if errors.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if !metav1.IsControlledBy(pdb, ms) {
+		return -1, nil
+	}
+	if pdb.Status.ObservedGeneration < pdb.Generation {
+		return 0, nil
+	}
+
+	// The budget status only drops once the pods of deleted miners stop being ready, until
+	// then they use up disruptions it still reports as allowed
+	deleted, err := r.deletedMinerReadyPods(ctx, ms, miners)
+	if err != nil {
+		return 0, err
+	}
+	return max(int(pdb.Status.DisruptionsAllowed)-deleted, 0), nil
+}
+
+// deletedMinerReadyPods returns the number of ready pods of the MinerSet that do not belong
+// to one of the given miners, such as the pods of miners deleted by a previous reconcile.
+// Pods being deleted are not counted, the disruption controller no longer counts them as
+// healthy either.
+func (r *MinerSetReconciler) deletedMinerReadyPods(ctx context.Context, ms *appsv1alpha1.MinerSet, miners []*appsv1alpha1.Miner) (int, error) {
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.InNamespace(ms.Namespace), client.MatchingLabels{minerSetNameLabel: ms.Name}); err != nil {
+		return 0, err
+	}
+
+	podNames := sets.New[string]()
+	for _, miner := range miners {
+		podNames.Insert(minerPodName(miner))
+	}
+
+	count := 0
+	for idx := range podList.Items {
+		pod := &podList.Items[idx]
+		if podNames.Has(pod.Name) || !pod.DeletionTimestamp.IsZero() || !isPodReady(pod) {
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+// limitDisruptions returns the miners that can be deleted without deleting more than allowed
+// ready miners, and the number of miners held back. Miners that are not ready do not count
+// against the budget. A negative allowed deletes every miner.
+func limitDisruptions(miners []*appsv1alpha1.Miner, allowed int) ([]*appsv1alpha1.Miner, int) {
+	if allowed < 0 {
+		return miners, 0
+	}
+	deletable := make([]*appsv1alpha1.Miner, 0, len(miners))
+	for _, miner := range miners {
+		if miner.Status.Ready {
+			if allowed == 0 {
+				continue
+			}
+			allowed--
+		}
+		deletable = append(deletable, miner)
+	}
+	return deletable, len(miners) - len(deletable)
+}
+
+// MinerSetMinerLabels returns the labels the controller sets on the miners of the MinerSet:
+// the template labels without the reserved keys, along with the MinerSet and chain labels.
+func MinerSetMinerLabels(ms *appsv1alpha1.MinerSet) map[string]string {
+	minerLabels := make(map[string]string, len(ms.Spec.Template.Labels)+2)
 	for k, v := range ms.Spec.Template.Labels {
-		minerLabels[k] = v
+		if !reservedMinerMetadataKeys.Has(k) {
+			minerLabels[k] = v
+		}
 	}
 	minerLabels[minerSetNameLabel] = ms.Name
 	minerLabels[chainNameLabel] = ms.Spec.Template.Spec.ChainName
+	return minerLabels
+}
+
+func (r *MinerSetReconciler) computeDesiredMiner(ms *appsv1alpha1.MinerSet, existingMiner *appsv1alpha1.Miner, templateHash string) *appsv1alpha1.Miner {
+	minerLabels := MinerSetMinerLabels(ms)
 
 	minerAnnotations := make(map[string]string)
 	for k, v := range ms.Spec.Template.Annotations {
-		minerAnnotations[k] = v
+		if !reservedMinerMetadataKeys.Has(k) {
+			minerAnnotations[k] = v
+		}
 	}
+	minerAnnotations[templateHashAnnotation] = templateHash
 
 	miner := &appsv1alpha1.Miner{
 		ObjectMeta: metav1.ObjectMeta{
@@ -248,10 +774,12 @@ func (r *MinerSetReconciler) computeDesiredMiner(ms *appsv1alpha1.MinerSet, exis
 			Namespace:    ms.Namespace,
 			Labels:       minerLabels,
 			Annotations:  minerAnnotations,
-			Finalizers:   []string{minerSetFinalizer},
 		},
 		Spec: *ms.Spec.Template.Spec.DeepCopy(),
 	}
+	if !r.DisableFinalizers {
+		miner.Finalizers = []string{minerSetFinalizer}
+	}
 
 	if existingMiner != nil {
 		miner.Name = existingMiner.Name
@@ -269,26 +797,80 @@ func (r *MinerSetReconciler) getMinersToDelete(ms *appsv1alpha1.MinerSet, miners
 		return miners
 	}
 
-	var toDelete []*appsv1alpha1.Miner
-	switch ms.Spec.DeletePolicy {
+	// Protected miners are only deleted once no other candidates remain
+	var candidates, protected []*appsv1alpha1.Miner
+	for _, miner := range miners {
+		if miner.Annotations[minerProtectAnnotation] == "true" {
+			protected = append(protected, miner)
+		} else {
+			candidates = append(candidates, miner)
+		}
+	}
+
+	toDelete := selectMinersByDeletePolicy(ms.Spec.DeletePolicy, candidates, count)
+	if remaining := count - len(toDelete); remaining > 0 {
+		toDelete = append(toDelete, selectMinersByDeletePolicy(ms.Spec.DeletePolicy, protected, remaining)...)
+	}
+	return toDelete
+}
+
+// selectMinersByDeletePolicy returns up to count of the miners, picked according to the
+// delete policy.
+func selectMinersByDeletePolicy(policy appsv1alpha1.DeletePolicy, miners []*appsv1alpha1.Miner, count int) []*appsv1alpha1.Miner {
+	if count >= len(miners) {
+		return miners
+	}
+
+	switch policy {
 	case appsv1alpha1.DeletePolicyNewest:
-		toDelete = miners[:count]
+		return miners[:count]
 	case appsv1alpha1.DeletePolicyOldest:
-		toDelete = miners[len(miners)-count:]
+		return miners[len(miners)-count:]
+	case appsv1alpha1.DeletePolicyUnhealthyFirst:
+		ranked := make([]*appsv1alpha1.Miner, len(miners))
+		copy(ranked, miners)
+		sort.SliceStable(ranked, func(i, j int) bool {
+			return minerHealthScore(ranked[i]) < minerHealthScore(ranked[j])
+		})
+		return ranked[:count]
 	default: // Random
-		toDelete = miners[:count]
+		return miners[:count]
 	}
+}
 
-	return toDelete
+// minerHealthScore ranks how healthy a miner is, higher being healthier. The phase
+// weighs the most, followed by readiness, and each container restart lowers the
+// score without dropping a miner below one in a less healthy phase.
+func minerHealthScore(miner *appsv1alpha1.Miner) int {
+	score := 0
+	switch miner.Status.Phase {
+	case appsv1alpha1.MinerPhaseRunning:
+		score += 2000
+	case appsv1alpha1.MinerPhaseFailed, appsv1alpha1.MinerPhaseSucceeded, appsv1alpha1.MinerPhaseDraining, appsv1alpha1.MinerPhaseDeleting:
+	default: // Pending or Provisioning
+		score += 1000
+	}
+	if miner.Status.Ready {
+		score += 500
+	}
+
+	var restarts int32
+	for _, cs := range miner.Status.ContainerStatuses {
+		restarts += cs.RestartCount
+	}
+	return score - int(min(restarts, 499))
 }
 
-func (r *MinerSetReconciler) updateStatus(ctx context.Context, ms *appsv1alpha1.MinerSet, miners []*appsv1alpha1.Miner) error {
+// updateStatus computes the MinerSet status from its miners and writes it, unless it is
+// unchanged from the observed status.
+func (r *MinerSetReconciler) updateStatus(ctx context.Context, ms *appsv1alpha1.MinerSet, observed *appsv1alpha1.MinerSetStatus, miners []*appsv1alpha1.Miner, templateHash string) error {
 	log := log.FromContext(ctx)
 
 	templateLabel := labels.Set(ms.Spec.Template.Labels).AsSelectorPreValidated()
 	fullyLabeledReplicasCount := 0
 	readyReplicasCount := 0
 	availableReplicasCount := 0
+	var unavailableMiners []appsv1alpha1.UnavailableMiner
 
 	for _, miner := range miners {
 		if templateLabel.Matches(labels.Set(miner.Labels)) {
@@ -300,13 +882,27 @@ func (r *MinerSetReconciler) updateStatus(ctx context.Context, ms *appsv1alpha1.
 			if miner.Status.ObservedGeneration == miner.Generation {
 				availableReplicasCount++
 			}
+		} else {
+			unavailableMiners = append(unavailableMiners, appsv1alpha1.UnavailableMiner{
+				Name:  miner.Name,
+				Phase: miner.Status.Phase,
+			})
 		}
 	}
 
+	sort.Slice(unavailableMiners, func(i, j int) bool {
+		return unavailableMiners[i].Name < unavailableMiners[j].Name
+	})
+	if len(unavailableMiners) > maxUnavailableMinersReported {
+		unavailableMiners = unavailableMiners[:maxUnavailableMinersReported]
+	}
+	ms.Status.UnavailableMiners = unavailableMiners
+
 	ms.Status.Replicas = int32(len(miners))
 	ms.Status.FullyLabeledReplicas = int32(fullyLabeledReplicasCount)
 	ms.Status.ReadyReplicas = int32(readyReplicasCount)
 	ms.Status.AvailableReplicas = int32(availableReplicasCount)
+	ms.Status.ObservedTemplateHash = templateHash
 
 	if ms.Status.ReadyReplicas == ms.Status.Replicas {
 		condition.SetTrue(ms, condition.MinersReadyCondition)
@@ -314,7 +910,11 @@ func (r *MinerSetReconciler) updateStatus(ctx context.Context, ms *appsv1alpha1.
 		condition.SetFalse(ms, condition.MinersReadyCondition, condition.UnavailableReason, "Not all miners are ready")
 	}
 
-	if err := r.Status().Update(ctx, ms); err != nil {
+	if equality.Semantic.DeepEqual(observed, &ms.Status) {
+		return nil
+	}
+
+	if err := updateStatusWithRetry(ctx, r.Client, ms); err != nil {
 		log.Error(err, "Failed to update MinerSet status")
 		return err
 	}
@@ -335,12 +935,44 @@ func (r *MinerSetReconciler) waitForMinerCreation(ctx context.Context, miner *ap
 	})
 }
 
+// isChainReady returns true if the Chain referenced by the MinerSet template exists
+// and reports its ConfigMap as created.
+func (r *MinerSetReconciler) isChainReady(ctx context.Context, ms *appsv1alpha1.MinerSet) (bool, error) {
+	chain := &appsv1alpha1.Chain{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: ms.Namespace, Name: ms.Spec.Template.Spec.ChainName}, chain); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return condition.IsTrue(chain, condition.ConfigMapsCreatedCondition), nil
+}
+
 func (r *MinerSetReconciler) adoptOrphan(ctx context.Context, ms *appsv1alpha1.MinerSet, miner *appsv1alpha1.Miner) error {
 	patch := client.MergeFrom(miner.DeepCopy())
 	miner.OwnerReferences = append(miner.OwnerReferences, *metav1.NewControllerRef(ms, msKind))
 	return r.Patch(ctx, miner, patch)
 }
 
+// adoptsOrphans returns true if the MinerSet adopts matching miners without a controller,
+// which is the default for MinerSets created before the field existed.
+func adoptsOrphans(ms *appsv1alpha1.MinerSet) bool {
+	return ms.Spec.AdoptOrphans == nil || *ms.Spec.AdoptOrphans
+}
+
+// belongsToChain returns true if the miner is for the chain of the MinerSet template,
+// judged by both its spec and its chain label.
+func belongsToChain(ms *appsv1alpha1.MinerSet, miner *appsv1alpha1.Miner) bool {
+	chainName := ms.Spec.Template.Spec.ChainName
+	if miner.Spec.ChainName != chainName {
+		return false
+	}
+	if label, ok := miner.Labels[chainNameLabel]; ok && label != chainName {
+		return false
+	}
+	return true
+}
+
 func shouldExcludeMiner(ms *appsv1alpha1.MinerSet, miner *appsv1alpha1.Miner) bool {
 	if metav1.GetControllerOf(miner) != nil && !metav1.IsControlledBy(miner, ms) {
 		return true
@@ -348,10 +980,23 @@ func shouldExcludeMiner(ms *appsv1alpha1.MinerSet, miner *appsv1alpha1.Miner) bo
 	return false
 }
 
-// SetupWithManager sets up the controller with the Manager.
-func (r *MinerSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+// SetupWithManager sets up the controller with the Manager. Changes to the owned miners
+// reconcile their MinerSet, while its own status-only updates do not. Miners are only
+// created and deleted by the reconcile of their MinerSet, and the workqueue never hands out
+// the same MinerSet twice at once, so replica counts hold with MaxConcurrentReconciles above 1.
+func (r *MinerSetReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &appsv1alpha1.Miner{},
+		minerControllerUIDField, indexMinerByControllerUID); err != nil {
+		return err
+	}
+	r.indexedByController = true
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&appsv1alpha1.MinerSet{}).
+		For(&appsv1alpha1.MinerSet{}, builder.WithPredicates(minerSetChangedPredicate)).
+		Owns(&appsv1alpha1.Miner{}).
+		Owns(&corev1.Service{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
 		Named("minerset").
+		WithOptions(options).
 		Complete(r)
 }
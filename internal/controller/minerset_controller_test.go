@@ -18,12 +18,21 @@ package controller
 
 import (
 	"context"
+	"sync"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -114,6 +123,115 @@ var _ = Describe("MinerSet Controller", func() {
 			Expect(minerset.Status.Replicas).To(Equal(replicas))
 		})
 
+		It("should report the hash of the template it acted on", func() {
+			controllerReconciler := &MinerSetReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			minerset := &appsv1alpha1.MinerSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			Expect(minerset.Status.ObservedTemplateHash).To(Equal(mustTemplateHash(minerset.Spec.Template)))
+
+			minerList := &appsv1alpha1.MinerList{}
+			Expect(k8sClient.List(ctx, minerList, client.InNamespace("default"),
+				client.MatchingLabels{minerSetNameLabel: resourceName})).To(Succeed())
+			for _, miner := range minerList.Items {
+				Expect(miner.Annotations).To(HaveKeyWithValue(templateHashAnnotation, minerset.Status.ObservedTemplateHash))
+			}
+
+			By("Changing the template")
+			minerset.Spec.Template.Spec.MinerType = appsv1alpha1.MinerTypeMedium
+			Expect(k8sClient.Update(ctx, minerset)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			Expect(minerset.Status.ObservedTemplateHash).To(Equal(mustTemplateHash(minerset.Spec.Template)))
+		})
+
+		It("should follow replicas changed by an autoscaler between reconciles", func() {
+			controllerReconciler := &MinerSetReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Scaling the MinerSet as an autoscaler would")
+			minerset := &appsv1alpha1.MinerSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			minerset.Spec.Replicas = ptr.To[int32](5)
+			Expect(k8sClient.Update(ctx, minerset)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the miners follow the new replicas")
+			minerList := &appsv1alpha1.MinerList{}
+			Expect(k8sClient.List(ctx, minerList, client.InNamespace("default"))).To(Succeed())
+			Expect(minerList.Items).To(HaveLen(5))
+
+			By("Checking the controller left the replicas alone")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			Expect(minerset.Spec.Replicas).To(Equal(ptr.To[int32](5)))
+			Expect(minerset.Status.Replicas).To(Equal(int32(5)))
+		})
+
+		It("should refuse to patch the replicas of a MinerSet", func() {
+			controllerReconciler := &MinerSetReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			minerset := &appsv1alpha1.MinerSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			original := minerset.DeepCopy()
+			minerset.Spec.Replicas = ptr.To[int32](1)
+			Expect(controllerReconciler.patchMinerSet(ctx, original, minerset)).NotTo(Succeed())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			Expect(minerset.Spec.Replicas).To(Equal(ptr.To(replicas)))
+		})
+
+		It("should not add finalizers when finalizers are disabled", func() {
+			controllerReconciler := &MinerSetReconciler{
+				Client:            k8sClient,
+				Scheme:            k8sClient.Scheme(),
+				DisableFinalizers: true,
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			minerset := &appsv1alpha1.MinerSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			Expect(minerset.Finalizers).To(BeEmpty())
+
+			minerList := &appsv1alpha1.MinerList{}
+			Expect(k8sClient.List(ctx, minerList, client.InNamespace("default"),
+				client.MatchingLabels{minerSetNameLabel: resourceName})).To(Succeed())
+			Expect(minerList.Items).To(HaveLen(int(replicas)))
+			for _, miner := range minerList.Items {
+				Expect(miner.Finalizers).To(BeEmpty())
+			}
+		})
+
 		It("should scale up miners", func() {
 			By("Creating initial miners")
 			controllerReconciler := &MinerSetReconciler{
@@ -184,24 +302,120 @@ var _ = Describe("MinerSet Controller", func() {
 			Expect(len(minerList.Items)).To(Equal(int(newReplicas)))
 		})
 
-		It("should adopt orphan miners", func() {
-			By("Creating an orphan miner")
-			orphanMiner := &appsv1alpha1.Miner{
+		It("should assign ordinal miner names and reuse them after a scale down", func() {
+			minerset := &appsv1alpha1.MinerSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			minerset.Spec.NamingStrategy = appsv1alpha1.MinerNamingStrategyOrdinal
+			Expect(k8sClient.Update(ctx, minerset)).To(Succeed())
+
+			controllerReconciler := &MinerSetReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			minerNames := func() []string {
+				minerList := &appsv1alpha1.MinerList{}
+				Expect(k8sClient.List(ctx, minerList, client.InNamespace("default"),
+					client.MatchingLabels{minerSetNameLabel: resourceName})).To(Succeed())
+				var names []string
+				for _, miner := range minerList.Items {
+					names = append(names, miner.Name)
+				}
+				return names
+			}
+
+			By("Creating the miners")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(minerNames()).To(ConsistOf(resourceName+"-0", resourceName+"-1", resourceName+"-2"))
+
+			By("Scaling down to 1 replica, leaving gaps in the ordinals")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			minerset.Spec.Replicas = ptr.To(int32(1))
+			Expect(k8sClient.Update(ctx, minerset)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			remaining := minerNames()
+			Expect(remaining).To(HaveLen(1))
+
+			By("Scaling back up to 3 replicas")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			minerset.Spec.Replicas = ptr.To(int32(3))
+			Expect(k8sClient.Update(ctx, minerset)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the freed ordinals were reused")
+			Expect(minerNames()).To(ConsistOf(resourceName+"-0", resourceName+"-1", resourceName+"-2"))
+			Expect(minerNames()).To(ContainElement(remaining[0]))
+		})
+
+		It("should not create miners in a terminating namespace", func() {
+			By("Creating a MinerSet in a namespace that is then deleted")
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "minerset-terminating"}}
+			Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+			minerset := &appsv1alpha1.MinerSet{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      "orphan-miner",
-					Namespace: "default",
-					Labels: map[string]string{
-						"app": "miner",
-					},
+					Name:      resourceName,
+					Namespace: namespace.Name,
 				},
-				Spec: appsv1alpha1.MinerSpec{
-					ChainName: "test-chain",
-					MinerType: appsv1alpha1.MinerTypeSmall,
+				Spec: appsv1alpha1.MinerSetSpec{
+					Replicas: ptr.To(int32(2)),
+					Template: appsv1alpha1.MinerTemplateSpec{
+						Spec: appsv1alpha1.MinerSpec{
+							ChainName: "test-chain",
+							MinerType: appsv1alpha1.MinerTypeSmall,
+						},
+					},
 				},
 			}
-			Expect(k8sClient.Create(ctx, orphanMiner)).To(Succeed())
+			Expect(k8sClient.Create(ctx, minerset)).To(Succeed())
+			// Without a namespace controller the namespace stays Terminating
+			Expect(k8sClient.Delete(ctx, namespace)).To(Succeed())
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(namespace), namespace)).To(Succeed())
+			Expect(namespace.Status.Phase).To(Equal(corev1.NamespaceTerminating))
+
+			By("Reconciling the MinerSet")
+			controllerReconciler := &MinerSetReconciler{
+				Client:            k8sClient,
+				Scheme:            k8sClient.Scheme(),
+				DisableFinalizers: true,
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(minerset),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+
+			By("Checking no miners were created and the namespace is reported")
+			minerList := &appsv1alpha1.MinerList{}
+			Expect(k8sClient.List(ctx, minerList, client.InNamespace(namespace.Name))).To(Succeed())
+			Expect(minerList.Items).To(BeEmpty())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(minerset), minerset)).To(Succeed())
+			cond := condition.Get(minerset, condition.MinersCreatedCondition)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(string(condition.NamespaceTerminatingReason)))
+		})
+
+		It("should only delete as many ready miners as the PodDisruptionBudget allows", func() {
+			By("Creating initial miners with a PodDisruptionBudget")
+			minerset := &appsv1alpha1.MinerSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			minerset.Spec.PodDisruptionBudget = &appsv1alpha1.MinerSetPodDisruptionBudget{
+				MinAvailable: ptr.To(intstr.FromInt32(2)),
+			}
+			Expect(k8sClient.Update(ctx, minerset)).To(Succeed())
 
-			By("Reconciling MinerSet")
 			controllerReconciler := &MinerSetReconciler{
 				Client: k8sClient,
 				Scheme: k8sClient.Scheme(),
@@ -212,14 +426,1058 @@ var _ = Describe("MinerSet Controller", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 
-			By("Checking if orphan miner was adopted")
-			adoptedMiner := &appsv1alpha1.Miner{}
-			Expect(k8sClient.Get(ctx, client.ObjectKey{Name: "orphan-miner", Namespace: "default"}, adoptedMiner)).To(Succeed())
-			Expect(adoptedMiner.OwnerReferences).NotTo(BeEmpty())
-			Expect(adoptedMiner.OwnerReferences[0].Kind).To(Equal("MinerSet"))
+			pdb := &policyv1.PodDisruptionBudget{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, pdb)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, pdb)
 
-			By("Cleaning up orphan miner")
-			Expect(k8sClient.Delete(ctx, adoptedMiner)).To(Succeed())
+			By("Making the miners ready")
+			minerList := &appsv1alpha1.MinerList{}
+			Expect(k8sClient.List(ctx, minerList, client.InNamespace("default"),
+				client.MatchingLabels{minerSetNameLabel: resourceName})).To(Succeed())
+			for i := range minerList.Items {
+				miner := &minerList.Items[i]
+				miner.Status.Phase = appsv1alpha1.MinerPhaseRunning
+				miner.Status.Ready = true
+				Expect(k8sClient.Status().Update(ctx, miner)).To(Succeed())
+			}
+
+			By("Scaling down to 1 replica before the budget status is observed")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			minerset.Spec.Replicas = ptr.To(int32(1))
+			Expect(k8sClient.Update(ctx, minerset)).To(Succeed())
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(scaleDownBlockedRequeueInterval))
+
+			By("Checking the miners were kept and the Resized condition")
+			liveMiners := func() int {
+				minerList := &appsv1alpha1.MinerList{}
+				Expect(k8sClient.List(ctx, minerList, client.InNamespace("default"),
+					client.MatchingLabels{minerSetNameLabel: resourceName})).To(Succeed())
+				count := 0
+				for _, miner := range minerList.Items {
+					if miner.DeletionTimestamp.IsZero() {
+						count++
+					}
+				}
+				return count
+			}
+			Expect(liveMiners()).To(Equal(int(replicas)))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			cond := condition.Get(minerset, condition.ResizedCondition)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(string(condition.DisruptionBudgetBlockedReason)))
+			Expect(cond.Message).To(ContainSubstring("2 ready miners cannot be deleted yet"))
+
+			By("Allowing a single disruption")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, pdb)).To(Succeed())
+			pdb.Status.ObservedGeneration = pdb.Generation
+			pdb.Status.DisruptionsAllowed = 1
+			pdb.Status.CurrentHealthy = 3
+			pdb.Status.DesiredHealthy = 2
+			pdb.Status.ExpectedPods = 3
+			Expect(k8sClient.Status().Update(ctx, pdb)).To(Succeed())
+
+			result, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(scaleDownBlockedRequeueInterval))
+			Expect(liveMiners()).To(Equal(int(replicas) - 1))
 		})
-	})
-})
+
+		It("should not delete more ready miners than the PodDisruptionBudget allows across reconciles", func() {
+			By("Creating initial miners with a PodDisruptionBudget")
+			minerset := &appsv1alpha1.MinerSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			minerset.Spec.PodDisruptionBudget = &appsv1alpha1.MinerSetPodDisruptionBudget{
+				MinAvailable: ptr.To(intstr.FromInt32(2)),
+			}
+			Expect(k8sClient.Update(ctx, minerset)).To(Succeed())
+
+			controllerReconciler := &MinerSetReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			pdb := &policyv1.PodDisruptionBudget{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, pdb)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, pdb)
+
+			By("Making the miners and their pods ready")
+			minerList := &appsv1alpha1.MinerList{}
+			Expect(k8sClient.List(ctx, minerList, client.InNamespace("default"),
+				client.MatchingLabels{minerSetNameLabel: resourceName})).To(Succeed())
+			for i := range minerList.Items {
+				miner := &minerList.Items[i]
+				miner.Status.Phase = appsv1alpha1.MinerPhaseRunning
+				miner.Status.Ready = true
+				Expect(k8sClient.Status().Update(ctx, miner)).To(Succeed())
+
+				// Nothing deletes the pods in envtest, like a pod still draining
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      minerPodName(miner),
+						Namespace: "default",
+						Labels:    map[string]string{minerSetNameLabel: resourceName},
+					},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "miner", Image: "busybox"}},
+					},
+				}
+				Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+				DeferCleanup(k8sClient.Delete, ctx, pod)
+				pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+				Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+			}
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, pdb)).To(Succeed())
+			pdb.Status.ObservedGeneration = pdb.Generation
+			pdb.Status.DisruptionsAllowed = 1
+			pdb.Status.CurrentHealthy = 3
+			pdb.Status.DesiredHealthy = 2
+			pdb.Status.ExpectedPods = 3
+			Expect(k8sClient.Status().Update(ctx, pdb)).To(Succeed())
+
+			By("Scaling down to 1 replica and reconciling twice without a budget status update")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			minerset.Spec.Replicas = ptr.To(int32(1))
+			Expect(k8sClient.Update(ctx, minerset)).To(Succeed())
+
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("Checking a single miner was deleted")
+			Expect(k8sClient.List(ctx, minerList, client.InNamespace("default"),
+				client.MatchingLabels{minerSetNameLabel: resourceName})).To(Succeed())
+			live := 0
+			for _, miner := range minerList.Items {
+				if miner.DeletionTimestamp.IsZero() {
+					live++
+				}
+			}
+			Expect(live).To(Equal(int(replicas) - 1))
+		})
+
+		It("should report the MinerSet resized only once its miners are running", func() {
+			controllerReconciler := &MinerSetReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			By("Scaling up and reconciling again while the miners are provisioning")
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			minerset := &appsv1alpha1.MinerSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			cond := condition.Get(minerset, condition.ResizedCondition)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(string(condition.ProvisioningReason)))
+
+			By("Reporting the miners as Running")
+			minerList := &appsv1alpha1.MinerList{}
+			Expect(k8sClient.List(ctx, minerList, client.InNamespace("default"),
+				client.MatchingLabels{minerSetNameLabel: resourceName})).To(Succeed())
+			Expect(minerList.Items).To(HaveLen(int(replicas)))
+			for i := range minerList.Items {
+				miner := &minerList.Items[i]
+				miner.Status.Phase = appsv1alpha1.MinerPhaseRunning
+				Expect(k8sClient.Status().Update(ctx, miner)).To(Succeed())
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			Expect(condition.IsTrue(minerset, condition.ResizedCondition)).To(BeTrue())
+		})
+
+		It("should throttle miner creation while miners are provisioning", func() {
+			By("Limiting the number of provisioning miners")
+			minerset := &appsv1alpha1.MinerSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			minerset.Spec.MaxProvisioning = ptr.To[int32](1)
+			Expect(k8sClient.Update(ctx, minerset)).To(Succeed())
+
+			controllerReconciler := &MinerSetReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			listMiners := func() []appsv1alpha1.Miner {
+				minerList := &appsv1alpha1.MinerList{}
+				Expect(k8sClient.List(ctx, minerList, client.InNamespace("default"),
+					client.MatchingLabels{minerSetNameLabel: resourceName})).To(Succeed())
+				return minerList.Items
+			}
+
+			By("Reconciling twice while the first miner is provisioning")
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			miners := listMiners()
+			Expect(miners).To(HaveLen(1))
+
+			By("Reporting the miner as Running")
+			miners[0].Status.Phase = appsv1alpha1.MinerPhaseRunning
+			Expect(k8sClient.Status().Update(ctx, &miners[0])).To(Succeed())
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listMiners()).To(HaveLen(2))
+		})
+
+		It("should scale to zero and report a resized and ready MinerSet", func() {
+			By("Creating initial miners")
+			controllerReconciler := &MinerSetReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Scaling down to zero replicas")
+			minerset := &appsv1alpha1.MinerSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			minerset.Spec.Replicas = ptr.To(int32(0))
+			Expect(k8sClient.Update(ctx, minerset)).To(Succeed())
+
+			for range 2 {
+				_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("Checking all miners are gone")
+			minerList := &appsv1alpha1.MinerList{}
+			Expect(k8sClient.List(ctx, minerList, client.InNamespace("default"),
+				client.MatchingLabels{minerSetNameLabel: resourceName})).To(Succeed())
+			Expect(minerList.Items).To(BeEmpty())
+
+			By("Checking the MinerSet status")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			Expect(minerset.Status.Replicas).To(BeZero())
+			Expect(minerset.Status.ReadyReplicas).To(BeZero())
+			Expect(condition.IsTrue(minerset, condition.ResizedCondition)).To(BeTrue())
+			Expect(condition.IsTrue(minerset, condition.MinersReadyCondition)).To(BeTrue())
+		})
+
+		It("should not count terminating miners as replicas", func() {
+			By("Creating initial miners")
+			controllerReconciler := &MinerSetReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Deleting a miner held back by a finalizer")
+			minerList := &appsv1alpha1.MinerList{}
+			Expect(k8sClient.List(ctx, minerList, client.InNamespace("default"))).To(Succeed())
+			Expect(minerList.Items).To(HaveLen(int(replicas)))
+			terminating := &minerList.Items[0]
+			controllerutil.AddFinalizer(terminating, "test.onex.io/block")
+			Expect(k8sClient.Update(ctx, terminating)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, terminating)).To(Succeed())
+			DeferCleanup(func() {
+				miner := &appsv1alpha1.Miner{}
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(terminating), miner); err == nil {
+					controllerutil.RemoveFinalizer(miner, "test.onex.io/block")
+					Expect(k8sClient.Update(ctx, miner)).To(Succeed())
+				}
+			})
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking a replacement was created for the terminating miner")
+			Expect(k8sClient.List(ctx, minerList, client.InNamespace("default"))).To(Succeed())
+			active := 0
+			for _, miner := range minerList.Items {
+				if miner.DeletionTimestamp.IsZero() {
+					active++
+				}
+			}
+			Expect(active).To(Equal(int(replicas)))
+			Expect(minerList.Items).To(HaveLen(int(replicas) + 1))
+
+			By("Checking the terminating miner is not reported as a replica")
+			minerset := &appsv1alpha1.MinerSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			Expect(minerset.Status.Replicas).To(Equal(replicas))
+		})
+
+		It("should create a headless Service selecting its miners", func() {
+			By("Enabling the headless Service")
+			minerset := &appsv1alpha1.MinerSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			minerset.Spec.HeadlessService = true
+			Expect(k8sClient.Update(ctx, minerset)).To(Succeed())
+
+			controllerReconciler := &MinerSetReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the Service")
+			svc := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, svc)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, svc)
+			Expect(svc.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+			Expect(svc.Spec.Selector).To(Equal(map[string]string{minerSetNameLabel: resourceName}))
+			Expect(metav1.IsControlledBy(svc, minerset)).To(BeTrue())
+
+			By("Checking the ServiceRef")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			Expect(minerset.Status.ServiceRef).To(Equal(&appsv1alpha1.LocalObjectReference{Name: resourceName}))
+		})
+
+		It("should create a PodDisruptionBudget and keep it in sync with the replicas", func() {
+			By("Requesting a PodDisruptionBudget with more available miners than replicas")
+			minerset := &appsv1alpha1.MinerSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			minerset.Spec.PodDisruptionBudget = &appsv1alpha1.MinerSetPodDisruptionBudget{
+				MinAvailable: ptr.To(intstr.FromInt32(5)),
+			}
+			Expect(k8sClient.Update(ctx, minerset)).To(Succeed())
+
+			controllerReconciler := &MinerSetReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the PodDisruptionBudget")
+			pdb := &policyv1.PodDisruptionBudget{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, pdb)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, pdb)
+			Expect(metav1.IsControlledBy(pdb, minerset)).To(BeTrue())
+			Expect(pdb.Spec.Selector.MatchLabels).To(Equal(map[string]string{minerSetNameLabel: resourceName}))
+			Expect(*pdb.Spec.MinAvailable).To(Equal(intstr.FromInt32(replicas)))
+
+			By("Scaling down")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			minerset.Spec.Replicas = ptr.To(int32(2))
+			Expect(k8sClient.Update(ctx, minerset)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the PodDisruptionBudget follows the replicas")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, pdb)).To(Succeed())
+			Expect(*pdb.Spec.MinAvailable).To(Equal(intstr.FromInt32(2)))
+		})
+
+		It("should report the names of miners that are not ready", func() {
+			By("Creating the miners")
+			controllerReconciler := &MinerSetReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Marking one miner as running")
+			minerList := &appsv1alpha1.MinerList{}
+			Expect(k8sClient.List(ctx, minerList, client.InNamespace("default"))).To(Succeed())
+			Expect(minerList.Items).To(HaveLen(int(replicas)))
+			readyMiner := minerList.Items[0]
+			readyMiner.Status.Phase = appsv1alpha1.MinerPhaseRunning
+			Expect(k8sClient.Status().Update(ctx, &readyMiner)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the unavailable miners list")
+			minerset := &appsv1alpha1.MinerSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			Expect(minerset.Status.UnavailableMiners).To(HaveLen(int(replicas) - 1))
+			for _, unavailable := range minerset.Status.UnavailableMiners {
+				Expect(unavailable.Name).NotTo(Equal(readyMiner.Name))
+				Expect(unavailable.Phase).NotTo(Equal(appsv1alpha1.MinerPhaseRunning))
+			}
+		})
+
+		It("should wait for the chain ConfigMap before creating miners", func() {
+			By("Enabling WaitForChainReady without a ready chain")
+			minerset := &appsv1alpha1.MinerSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			minerset.Spec.WaitForChainReady = true
+			Expect(k8sClient.Update(ctx, minerset)).To(Succeed())
+
+			By("Reconciling MinerSet")
+			controllerReconciler := &MinerSetReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(chainReadyRequeueInterval))
+
+			By("Checking no miners were created")
+			minerList := &appsv1alpha1.MinerList{}
+			Expect(k8sClient.List(ctx, minerList, client.InNamespace("default"))).To(Succeed())
+			Expect(minerList.Items).To(BeEmpty())
+
+			By("Checking the ChainReady condition")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			Expect(condition.IsFalse(minerset, condition.ChainReadyCondition)).To(BeTrue())
+			Expect(condition.Get(minerset, condition.ChainReadyCondition).Reason).To(Equal(string(condition.WaitingForChainReason)))
+		})
+
+		It("should report miners rejected by the API server", func() {
+			By("Reconciling MinerSet with a client that submits an invalid MinerType")
+			controllerReconciler := &MinerSetReconciler{
+				Client: &invalidMinerTypeClient{Client: k8sClient},
+				Scheme: k8sClient.Scheme(),
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(invalidTemplateRequeueInterval))
+
+			By("Checking no miners were created")
+			minerList := &appsv1alpha1.MinerList{}
+			Expect(k8sClient.List(ctx, minerList, client.InNamespace("default"))).To(Succeed())
+			Expect(minerList.Items).To(BeEmpty())
+
+			By("Checking the MinersCreated condition")
+			minerset := &appsv1alpha1.MinerSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			Expect(condition.IsFalse(minerset, condition.MinersCreatedCondition)).To(BeTrue())
+			cond := condition.Get(minerset, condition.MinersCreatedCondition)
+			Expect(cond.Reason).To(Equal(string(condition.InvalidConfigurationReason)))
+			Expect(cond.Message).To(ContainSubstring("minerType"))
+		})
+
+		It("should not write status when nothing changed", func() {
+			By("Reconciling until the miners are created and the MinerSet is resized")
+			countingClient := &statusWriteCountingClient{Client: k8sClient}
+			controllerReconciler := &MinerSetReconciler{
+				Client: countingClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			writes := countingClient.statusWrites
+
+			By("Reconciling again without any change")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(countingClient.statusWrites).To(Equal(writes))
+		})
+
+		It("should label the MinerSet with its chain without spurious writes", func() {
+			writeClient := &minerSetWriteCountingClient{Client: k8sClient}
+			controllerReconciler := &MinerSetReconciler{
+				Client: writeClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			minerset := &appsv1alpha1.MinerSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			Expect(minerset.Labels).To(HaveKeyWithValue(chainNameLabel, "test-chain"))
+
+			By("Reconciling again with the label already set")
+			writes := writeClient.writes
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(writeClient.writes).To(Equal(writes))
+		})
+
+		It("should patch metadata and update status without clobbering concurrent changes", func() {
+			controllerReconciler := &MinerSetReconciler{
+				Client: &concurrentLabelClient{Client: k8sClient, labels: map[string]string{"team": "mining"}},
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the concurrent label, the controller metadata and the status are all kept")
+			minerset := &appsv1alpha1.MinerSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			Expect(minerset.Labels).To(HaveKeyWithValue("team", "mining"))
+			Expect(minerset.Labels).To(HaveKeyWithValue(chainNameLabel, "test-chain"))
+			Expect(minerset.Finalizers).To(ContainElement(minerSetFinalizer))
+			Expect(minerset.Status.Replicas).To(Equal(replicas))
+		})
+
+		It("should reconcile different MinerSets concurrently without interference", func() {
+			By("Creating MinerSets with disjoint selectors")
+			names := []string{"concurrent-a", "concurrent-b"}
+			for i, name := range names {
+				resource := &appsv1alpha1.MinerSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: "default",
+					},
+					Spec: appsv1alpha1.MinerSetSpec{
+						Replicas: ptr.To(int32(i + 1)),
+						Template: appsv1alpha1.MinerTemplateSpec{
+							Spec: appsv1alpha1.MinerSpec{
+								ChainName: "test-chain",
+								MinerType: appsv1alpha1.MinerTypeSmall,
+							},
+							ObjectMeta: appsv1alpha1.ObjectMeta{
+								Labels: map[string]string{"app": name},
+							},
+						},
+						Selector: metav1.LabelSelector{
+							MatchLabels: map[string]string{"app": name},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(resource), resource)).To(Succeed())
+					resource.Finalizers = nil
+					Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+					Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+				})
+			}
+
+			By("Reconciling both MinerSets at the same time")
+			controllerReconciler := &MinerSetReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			var wg sync.WaitGroup
+			for _, name := range names {
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+						NamespacedName: types.NamespacedName{Name: name, Namespace: "default"},
+					})
+					Expect(err).NotTo(HaveOccurred())
+				}()
+			}
+			wg.Wait()
+
+			By("Checking each MinerSet created only its own miners")
+			for i, name := range names {
+				minerList := &appsv1alpha1.MinerList{}
+				Expect(k8sClient.List(ctx, minerList, client.InNamespace("default"),
+					client.MatchingLabels{minerSetNameLabel: name})).To(Succeed())
+				Expect(minerList.Items).To(HaveLen(i + 1))
+				for _, miner := range minerList.Items {
+					Expect(miner.Labels).To(HaveKeyWithValue("app", name))
+				}
+			}
+		})
+
+		It("should report a selector that cannot be converted", func() {
+			By("Using a selector with an unknown operator")
+			minerset := &appsv1alpha1.MinerSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			minerset.Spec.Selector = metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "app", Operator: "Bogus", Values: []string{"miner"}},
+				},
+			}
+			Expect(k8sClient.Update(ctx, minerset)).To(Succeed())
+
+			By("Reconciling MinerSet")
+			controllerReconciler := &MinerSetReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the MinersCreated condition")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			Expect(condition.IsFalse(minerset, condition.MinersCreatedCondition)).To(BeTrue())
+			Expect(condition.Get(minerset, condition.MinersCreatedCondition).Reason).To(Equal(string(condition.SelectorInvalidReason)))
+		})
+
+		It("should select miners with set-based selectors", func() {
+			By("Using In and NotIn expressions in the selector")
+			minerset := &appsv1alpha1.MinerSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, minerset)).To(Succeed())
+			minerset.Spec.Selector = metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"miner"}},
+					{Key: "tier", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"canary"}},
+				},
+			}
+			Expect(k8sClient.Update(ctx, minerset)).To(Succeed())
+
+			By("Creating a selected and an excluded orphan miner")
+			selected := &appsv1alpha1.Miner{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "selected-miner",
+					Namespace: "default",
+					Labels:    map[string]string{"app": "miner"},
+				},
+				Spec: appsv1alpha1.MinerSpec{
+					ChainName: "test-chain",
+					MinerType: appsv1alpha1.MinerTypeSmall,
+				},
+			}
+			Expect(k8sClient.Create(ctx, selected)).To(Succeed())
+			excluded := &appsv1alpha1.Miner{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "canary-miner",
+					Namespace: "default",
+					Labels:    map[string]string{"app": "miner", "tier": "canary"},
+				},
+				Spec: appsv1alpha1.MinerSpec{
+					ChainName: "test-chain",
+					MinerType: appsv1alpha1.MinerTypeSmall,
+				},
+			}
+			Expect(k8sClient.Create(ctx, excluded)).To(Succeed())
+
+			By("Reconciling MinerSet")
+			controllerReconciler := &MinerSetReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking only the selected miner was adopted")
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(selected), selected)).To(Succeed())
+			Expect(metav1.IsControlledBy(selected, minerset)).To(BeTrue())
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(excluded), excluded)).To(Succeed())
+			Expect(excluded.OwnerReferences).To(BeEmpty())
+
+			By("Checking the adopted miner counts towards the replicas")
+			minerList := &appsv1alpha1.MinerList{}
+			Expect(k8sClient.List(ctx, minerList, client.InNamespace("default"),
+				client.MatchingLabels{minerSetNameLabel: resourceName})).To(Succeed())
+			Expect(minerList.Items).To(HaveLen(int(replicas) - 1))
+		})
+
+		It("should adopt orphan miners", func() {
+			By("Creating an orphan miner")
+			orphanMiner := &appsv1alpha1.Miner{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "orphan-miner",
+					Namespace: "default",
+					Labels: map[string]string{
+						"app": "miner",
+					},
+				},
+				Spec: appsv1alpha1.MinerSpec{
+					ChainName: "test-chain",
+					MinerType: appsv1alpha1.MinerTypeSmall,
+				},
+			}
+			Expect(k8sClient.Create(ctx, orphanMiner)).To(Succeed())
+
+			By("Reconciling MinerSet")
+			controllerReconciler := &MinerSetReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking if orphan miner was adopted")
+			adoptedMiner := &appsv1alpha1.Miner{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Name: "orphan-miner", Namespace: "default"}, adoptedMiner)).To(Succeed())
+			Expect(adoptedMiner.OwnerReferences).NotTo(BeEmpty())
+			Expect(adoptedMiner.OwnerReferences[0].Kind).To(Equal("MinerSet"))
+
+			By("Cleaning up orphan miner")
+			Expect(k8sClient.Delete(ctx, adoptedMiner)).To(Succeed())
+		})
+
+		It("should not adopt orphan miners when adoption is disabled", func() {
+			By("Disabling orphan adoption on the MinerSet")
+			ms := &appsv1alpha1.MinerSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ms)).To(Succeed())
+			ms.Spec.AdoptOrphans = ptr.To(false)
+			Expect(k8sClient.Update(ctx, ms)).To(Succeed())
+
+			By("Creating an orphan miner")
+			orphanMiner := &appsv1alpha1.Miner{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "orphan-miner",
+					Namespace: "default",
+					Labels: map[string]string{
+						"app": "miner",
+					},
+				},
+				Spec: appsv1alpha1.MinerSpec{
+					ChainName: "test-chain",
+					MinerType: appsv1alpha1.MinerTypeSmall,
+				},
+			}
+			Expect(k8sClient.Create(ctx, orphanMiner)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, orphanMiner)).To(Succeed())
+			})
+
+			By("Reconciling MinerSet")
+			controllerReconciler := &MinerSetReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the orphan miner was not adopted")
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(orphanMiner), orphanMiner)).To(Succeed())
+			Expect(orphanMiner.OwnerReferences).To(BeEmpty())
+
+			By("Checking the MinerSet created its full replica count")
+			minerList := &appsv1alpha1.MinerList{}
+			Expect(k8sClient.List(ctx, minerList, client.InNamespace("default"),
+				client.MatchingLabels{minerSetNameLabel: resourceName})).To(Succeed())
+			Expect(minerList.Items).To(HaveLen(int(replicas)))
+		})
+
+		It("should not adopt orphan miners of another chain", func() {
+			By("Creating an orphan miner of another chain")
+			foreignMiner := &appsv1alpha1.Miner{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foreign-miner",
+					Namespace: "default",
+					Labels: map[string]string{
+						"app":          "miner",
+						chainNameLabel: "other-chain",
+					},
+				},
+				Spec: appsv1alpha1.MinerSpec{
+					ChainName: "other-chain",
+					MinerType: appsv1alpha1.MinerTypeSmall,
+				},
+			}
+			Expect(k8sClient.Create(ctx, foreignMiner)).To(Succeed())
+
+			By("Reconciling MinerSet")
+			controllerReconciler := &MinerSetReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the foreign miner was not adopted")
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(foreignMiner), foreignMiner)).To(Succeed())
+			Expect(foreignMiner.OwnerReferences).To(BeEmpty())
+
+			By("Checking the MinerSet created its full replica count")
+			minerList := &appsv1alpha1.MinerList{}
+			Expect(k8sClient.List(ctx, minerList, client.InNamespace("default"),
+				client.MatchingLabels{minerSetNameLabel: resourceName})).To(Succeed())
+			Expect(minerList.Items).To(HaveLen(int(replicas)))
+		})
+	})
+})
+
+var _ = Describe("getMinersToDelete", func() {
+	newMiners := func(names ...string) []*appsv1alpha1.Miner {
+		miners := make([]*appsv1alpha1.Miner, 0, len(names))
+		for _, name := range names {
+			miners = append(miners, &appsv1alpha1.Miner{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		}
+		return miners
+	}
+	protect := func(miner *appsv1alpha1.Miner) {
+		miner.Annotations = map[string]string{minerProtectAnnotation: "true"}
+	}
+	names := func(miners []*appsv1alpha1.Miner) []string {
+		var result []string
+		for _, miner := range miners {
+			result = append(result, miner.Name)
+		}
+		return result
+	}
+
+	DescribeTable("should keep protected miners while other candidates remain",
+		func(policy appsv1alpha1.DeletePolicy, count int, expected []string) {
+			ms := &appsv1alpha1.MinerSet{Spec: appsv1alpha1.MinerSetSpec{DeletePolicy: policy}}
+			miners := newMiners("a", "b", "c", "d")
+			protect(miners[0])
+			protect(miners[3])
+
+			r := &MinerSetReconciler{}
+			Expect(names(r.getMinersToDelete(ms, miners, count))).To(Equal(expected))
+		},
+		Entry("newest", appsv1alpha1.DeletePolicyNewest, 2, []string{"b", "c"}),
+		Entry("oldest", appsv1alpha1.DeletePolicyOldest, 2, []string{"b", "c"}),
+		Entry("random", appsv1alpha1.DeletePolicyRandom, 1, []string{"b"}),
+		Entry("newest falling back to protected", appsv1alpha1.DeletePolicyNewest, 3, []string{"b", "c", "a"}),
+		Entry("oldest falling back to protected", appsv1alpha1.DeletePolicyOldest, 3, []string{"b", "c", "d"}),
+	)
+
+	It("should delete the least healthy miners first", func() {
+		ms := &appsv1alpha1.MinerSet{Spec: appsv1alpha1.MinerSetSpec{DeletePolicy: appsv1alpha1.DeletePolicyUnhealthyFirst}}
+		miners := newMiners("ready", "restarting", "pending", "failed", "not-ready")
+		miners[0].Status = appsv1alpha1.MinerStatus{Phase: appsv1alpha1.MinerPhaseRunning, Ready: true}
+		miners[1].Status = appsv1alpha1.MinerStatus{
+			Phase: appsv1alpha1.MinerPhaseRunning,
+			Ready: true,
+			ContainerStatuses: []appsv1alpha1.MinerContainerStatus{
+				{Name: "miner", Ready: true, RestartCount: 3},
+			},
+		}
+		miners[2].Status = appsv1alpha1.MinerStatus{Phase: appsv1alpha1.MinerPhasePending}
+		miners[3].Status = appsv1alpha1.MinerStatus{Phase: appsv1alpha1.MinerPhaseFailed}
+		miners[4].Status = appsv1alpha1.MinerStatus{Phase: appsv1alpha1.MinerPhaseRunning}
+
+		r := &MinerSetReconciler{}
+		Expect(names(r.getMinersToDelete(ms, miners, 4))).To(Equal([]string{"failed", "pending", "not-ready", "restarting"}))
+		Expect(names(r.getMinersToDelete(ms, miners, 1))).To(Equal([]string{"failed"}))
+		Expect(names(miners)).To(Equal([]string{"ready", "restarting", "pending", "failed", "not-ready"}))
+	})
+
+	It("should ignore a protect annotation that is not true", func() {
+		ms := &appsv1alpha1.MinerSet{Spec: appsv1alpha1.MinerSetSpec{DeletePolicy: appsv1alpha1.DeletePolicyNewest}}
+		miners := newMiners("a", "b")
+		miners[0].Annotations = map[string]string{minerProtectAnnotation: "false"}
+
+		r := &MinerSetReconciler{}
+		Expect(names(r.getMinersToDelete(ms, miners, 1))).To(Equal([]string{"a"}))
+	})
+})
+
+var _ = Describe("minerSetChangedPredicate", func() {
+	newMinerSet := func() *appsv1alpha1.MinerSet {
+		return &appsv1alpha1.MinerSet{ObjectMeta: metav1.ObjectMeta{
+			Name:            "minerset",
+			Namespace:       "default",
+			Generation:      1,
+			ResourceVersion: "1",
+		}}
+	}
+
+	It("should not enqueue a status-only update", func() {
+		oldMinerSet := newMinerSet()
+		newStatus := oldMinerSet.DeepCopy()
+		newStatus.ResourceVersion = "2"
+		newStatus.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "minerset-controller", Subresource: "status"}}
+		newStatus.Status.ReadyReplicas = 3
+		condition.SetTrue(newStatus, condition.MinersReadyCondition)
+
+		Expect(minerSetChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldMinerSet, ObjectNew: newStatus})).To(BeFalse())
+	})
+
+	It("should enqueue spec, label and annotation updates", func() {
+		oldMinerSet := newMinerSet()
+
+		specChanged := oldMinerSet.DeepCopy()
+		specChanged.Generation = 2
+		specChanged.Spec.Replicas = ptr.To(int32(5))
+		Expect(minerSetChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldMinerSet, ObjectNew: specChanged})).To(BeTrue())
+
+		labelChanged := oldMinerSet.DeepCopy()
+		labelChanged.Labels = map[string]string{chainNameLabel: "chain"}
+		Expect(minerSetChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldMinerSet, ObjectNew: labelChanged})).To(BeTrue())
+
+		annotationChanged := oldMinerSet.DeepCopy()
+		annotationChanged.Annotations = map[string]string{"example.com/team": "mining"}
+		Expect(minerSetChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldMinerSet, ObjectNew: annotationChanged})).To(BeTrue())
+	})
+})
+
+var _ = Describe("listMiners", func() {
+	It("should only list the owned and orphaned miners through the controller UID index", func() {
+		ms := &appsv1alpha1.MinerSet{ObjectMeta: metav1.ObjectMeta{
+			Name:      "indexed-minerset",
+			Namespace: "default",
+			UID:       "minerset-uid",
+		}}
+		other := &appsv1alpha1.MinerSet{ObjectMeta: metav1.ObjectMeta{
+			Name:      "other-minerset",
+			Namespace: "default",
+			UID:       "other-uid",
+		}}
+		newMiner := func(name string, owner *appsv1alpha1.MinerSet) *appsv1alpha1.Miner {
+			miner := &appsv1alpha1.Miner{ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{"app": "indexed"},
+			}}
+			if owner != nil {
+				miner.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(owner, msKind)}
+			}
+			return miner
+		}
+		unmatched := newMiner("owned-unmatched", ms)
+		unmatched.Labels = map[string]string{"app": "other"}
+
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(k8sClient.Scheme()).
+			WithIndex(&appsv1alpha1.Miner{}, minerControllerUIDField, indexMinerByControllerUID).
+			WithObjects(newMiner("owned", ms), newMiner("orphan", nil), newMiner("foreign", other), unmatched).
+			Build()
+		r := &MinerSetReconciler{Client: fakeClient, indexedByController: true}
+		selector := labels.SelectorFromSet(labels.Set{"app": "indexed"})
+
+		names := func(miners []*appsv1alpha1.Miner) []string {
+			var result []string
+			for _, miner := range miners {
+				result = append(result, miner.Name)
+			}
+			return result
+		}
+
+		miners, err := r.listMiners(ctx, ms, selector)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(miners)).To(ConsistOf("owned", "orphan"))
+
+		By("Skipping orphans when adoption is disabled")
+		ms.Spec.AdoptOrphans = ptr.To(false)
+		miners, err = r.listMiners(ctx, ms, selector)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(miners)).To(ConsistOf("owned"))
+	})
+})
+
+// invalidMinerTypeClient is a client that replaces the MinerType of created miners
+// with a value rejected by the CRD schema. The MinerSet schema validates its template
+// the same way, so an invalid template cannot be created directly.
+type invalidMinerTypeClient struct {
+	client.Client
+}
+
+func (c *invalidMinerTypeClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if miner, ok := obj.(*appsv1alpha1.Miner); ok {
+		miner.Spec.MinerType = "invalid"
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+// minerSetWriteCountingClient is a client counting the updates and patches of MinerSets,
+// not including their status.
+type minerSetWriteCountingClient struct {
+	client.Client
+	writes int
+}
+
+func (c *minerSetWriteCountingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if _, ok := obj.(*appsv1alpha1.MinerSet); ok {
+		c.writes++
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *minerSetWriteCountingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if _, ok := obj.(*appsv1alpha1.MinerSet); ok {
+		c.writes++
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+// concurrentLabelClient is a client that adds labels to a MinerSet right before the first
+// patch of it, simulating another writer changing the MinerSet during a reconcile.
+type concurrentLabelClient struct {
+	client.Client
+	labels  map[string]string
+	patched bool
+}
+
+func (c *concurrentLabelClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if _, ok := obj.(*appsv1alpha1.MinerSet); ok && !c.patched {
+		c.patched = true
+		current := &appsv1alpha1.MinerSet{}
+		if err := c.Client.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+			return err
+		}
+		if current.Labels == nil {
+			current.Labels = make(map[string]string)
+		}
+		for k, v := range c.labels {
+			current.Labels[k] = v
+		}
+		if err := c.Client.Update(ctx, current); err != nil {
+			return err
+		}
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
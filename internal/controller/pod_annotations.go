@@ -0,0 +1,147 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// podAnnotationPrefix is the prefix of the miner annotations that are set on its pod.
+// Their values may be templates, e.g. "pod.miner.onex.io/chain: '{{ .ChainName }}'".
+// Other miner annotations describe the miner itself and are not propagated.
+const podAnnotationPrefix = "pod.miner.onex.io/"
+
+// podAnnotationData is the data available to templated miner annotations,
+// e.g. "{{ .ChainName }}" or "{{ .ConfigHash }}".
+type podAnnotationData struct {
+	// MinerName is the name of the miner.
+	MinerName string
+	// ChainName is the name of the chain the miner belongs to.
+	ChainName string
+	// ConfigHash is a hash of the chain ConfigMap data, empty if the ConfigMap does not exist yet.
+	ConfigHash string
+}
+
+// renderPodAnnotations returns the miner annotations under podAnnotationPrefix to set on
+// its pod. Values containing a template action are rendered with the given data, other
+// values are copied as is.
+func renderPodAnnotations(annotations map[string]string, data podAnnotationData) (map[string]string, error) {
+	rendered := make(map[string]string)
+	for key, value := range annotations {
+		if !strings.HasPrefix(key, podAnnotationPrefix) {
+			continue
+		}
+		if !strings.Contains(value, "{{") {
+			rendered[key] = value
+			continue
+		}
+
+		tmpl, err := template.New(key).Option("missingkey=error").Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template in annotation %q: %w", key, err)
+		}
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, data); err != nil {
+			return nil, fmt.Errorf("failed to render annotation %q: %w", key, err)
+		}
+		rendered[key] = sb.String()
+	}
+	return rendered, nil
+}
+
+// configHash returns a stable hash of the ConfigMap data.
+func configHash(data map[string]string) (string, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize the ConfigMap data: %w", err)
+	}
+
+	hasher := fnv.New32a()
+	_, _ = hasher.Write(raw)
+	return strconv.FormatUint(uint64(hasher.Sum32()), 36), nil
+}
+
+// splitCommand splits a command line into arguments. Arguments are separated by whitespace,
+// and single quotes, double quotes and backslashes escape it as in a POSIX shell. Variables,
+// globs and other expansions are not supported.
+func splitCommand(command string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, c := range command {
+		switch {
+		case escaped:
+			current.WriteRune(c)
+			escaped = false
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(c)
+			}
+		case quote == '"':
+			switch c {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				current.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inArg = true
+		case c == '\\':
+			escaped = true
+			inArg = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(c)
+			inArg = true
+		}
+	}
+
+	if escaped {
+		return nil, errors.New("command ends with an unescaped backslash")
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	if len(args) == 0 {
+		return nil, errors.New("command is empty")
+	}
+	return args, nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	appsv1alpha1 "github.com/ashwinyue/minerx/api/v1alpha1"
+)
+
+// DefaultPodGCInterval is the default interval between two pod garbage collections.
+const DefaultPodGCInterval = 10 * time.Minute
+
+// PodGarbageCollector periodically deletes miner pods whose Miner no longer exists.
+// Owner references normally take care of this, but externally managed pods and pods
+// with broken owner references would otherwise leak.
+type PodGarbageCollector struct {
+	client.Client
+
+	// APIReader reads Miners directly from the API server, so that a Miner missing
+	// from a lagging cache does not get its pod deleted.
+	APIReader client.Reader
+
+	// Interval is the time between two collections.
+	Interval time.Duration
+}
+
+// +kubebuilder:rbac:groups=apps.onex.io,resources=miners,verbs=get
+// +kubebuilder:rbac:groups="",resources=pods,verbs=list;delete
+
+var _ manager.LeaderElectionRunnable = &PodGarbageCollector{}
+
+// Start runs the collection every Interval until the context is cancelled.
+func (gc *PodGarbageCollector) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("pod-gc")
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := gc.collect(ctx); err != nil {
+			log.Error(err, "Failed to garbage collect miner pods")
+		}
+	}, gc.Interval)
+	return nil
+}
+
+// NeedLeaderElection makes only the leader delete pods.
+func (gc *PodGarbageCollector) NeedLeaderElection() bool {
+	return true
+}
+
+// collect deletes the pods carrying the miner name label whose Miner does not exist.
+func (gc *PodGarbageCollector) collect(ctx context.Context) error {
+	log := log.FromContext(ctx)
+
+	podList := &corev1.PodList{}
+	if err := gc.List(ctx, podList, client.HasLabels{minerNameLabel}); err != nil {
+		return err
+	}
+
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		// The label holds the pod name, which is truncated for long miner names
+		minerName := pod.Annotations[minerNameLabel]
+		if minerName == "" {
+			minerName = pod.Labels[minerNameLabel]
+		}
+		err := gc.APIReader.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: minerName}, &appsv1alpha1.Miner{})
+		if err == nil {
+			continue
+		}
+		if !errors.IsNotFound(err) {
+			return err
+		}
+
+		if err := gc.Delete(ctx, pod); err != nil && !errors.IsNotFound(err) {
+			log.Error(err, "Failed to delete orphaned pod", "pod", client.ObjectKeyFromObject(pod))
+			return err
+		}
+		log.Info("Deleted orphaned pod", "pod", client.ObjectKeyFromObject(pod), "miner", minerName)
+	}
+
+	return nil
+}
+
+// SetupWithManager adds the garbage collector to the Manager.
+func (gc *PodGarbageCollector) SetupWithManager(mgr ctrl.Manager) error {
+	return mgr.Add(gc)
+}
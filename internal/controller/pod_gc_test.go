@@ -0,0 +1,83 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/ashwinyue/minerx/api/v1alpha1"
+)
+
+var _ = Describe("Pod garbage collector", func() {
+	ctx := context.Background()
+
+	newMinerPod := func(name, minerName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{minerNameLabel: minerName},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "miner", Image: "nginx:alpine"}},
+			},
+		}
+	}
+
+	It("should delete pods whose Miner does not exist", func() {
+		By("Creating a Miner and a pod for it")
+		miner := &appsv1alpha1.Miner{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "gc-present-miner",
+				Namespace: "default",
+			},
+			Spec: appsv1alpha1.MinerSpec{
+				ChainName: "test-chain",
+				MinerType: appsv1alpha1.MinerTypeSmall,
+			},
+		}
+		Expect(k8sClient.Create(ctx, miner)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(ctx, miner)).To(Succeed())
+		})
+		kept := newMinerPod("gc-kept-pod", miner.Name)
+		Expect(k8sClient.Create(ctx, kept)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, kept))).To(Succeed())
+		})
+
+		By("Creating a pod for an absent Miner")
+		orphan := newMinerPod("gc-orphan-pod", "gc-absent-miner")
+		Expect(k8sClient.Create(ctx, orphan)).To(Succeed())
+
+		By("Running a collection")
+		gc := &PodGarbageCollector{Client: k8sClient, APIReader: k8sClient}
+		Expect(gc.collect(ctx)).To(Succeed())
+
+		By("Checking only the orphaned pod was deleted")
+		err := k8sClient.Get(ctx, client.ObjectKeyFromObject(orphan), &corev1.Pod{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(kept), &corev1.Pod{})).To(Succeed())
+	})
+})
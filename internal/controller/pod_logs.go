@@ -0,0 +1,97 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/ashwinyue/minerx/api/v1alpha1"
+)
+
+// PodLogReader reads the logs of a pod container. The controller-runtime client cannot
+// read the pods/log subresource, so the miner controller reads logs through it.
+type PodLogReader interface {
+	ReadLogs(ctx context.Context, namespace, name string, opts *corev1.PodLogOptions) ([]byte, error)
+}
+
+// clientsetPodLogReader reads pod logs with a typed Kubernetes clientset.
+type clientsetPodLogReader struct {
+	clientset kubernetes.Interface
+}
+
+// NewPodLogReader returns a PodLogReader reading pod logs from the API server of cfg.
+func NewPodLogReader(cfg *rest.Config) (PodLogReader, error) {
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &clientsetPodLogReader{clientset: clientset}, nil
+}
+
+// ReadLogs implements PodLogReader.
+func (r *clientsetPodLogReader) ReadLogs(ctx context.Context, namespace, name string, opts *corev1.PodLogOptions) ([]byte, error) {
+	return r.clientset.CoreV1().Pods(namespace).GetLogs(name, opts).DoRaw(ctx)
+}
+
+// failureLogs returns the tail of the miner container logs to append to the failure
+// message of the miner, or an empty string if the miner does not capture logs or they
+// cannot be read.
+func (r *MinerReconciler) failureLogs(ctx context.Context, miner *appsv1alpha1.Miner, pod *corev1.Pod) string {
+	if r.LogReader == nil || miner.Spec.FailureLogTailLines == nil {
+		return ""
+	}
+	log := log.FromContext(ctx)
+
+	container := containerName(miner)
+	logs, err := r.LogReader.ReadLogs(ctx, pod.Namespace, pod.Name, &corev1.PodLogOptions{
+		Container: container,
+		TailLines: ptr.To(int64(*miner.Spec.FailureLogTailLines)),
+	})
+	if err != nil {
+		// The failure is reported without logs rather than retried, the logs may be gone for good
+		log.Error(err, "Failed to read the logs of the failed pod", "pod", pod.Name, "container", container)
+		return ""
+	}
+
+	limit := ptr.Deref(miner.Spec.FailureLogLimitBytes, appsv1alpha1.DefaultMinerFailureLogLimitBytes)
+	logs = tailBytes(bytes.TrimRight(logs, "\n"), int(limit))
+	if len(logs) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\nLast logs of container %s:\n%s", container, logs)
+}
+
+// tailBytes returns at most limit bytes from the end of logs. When logs are cut, the
+// partial first line is dropped as well, unless it is the only line.
+func tailBytes(logs []byte, limit int) []byte {
+	if len(logs) <= limit {
+		return logs
+	}
+	logs = logs[len(logs)-limit:]
+	if i := bytes.IndexByte(logs, '\n'); i >= 0 {
+		logs = logs[i+1:]
+	}
+	return logs
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/ashwinyue/minerx/api/v1alpha1"
+)
+
+// fakePodLogReader returns fixed logs and records the options it was called with.
+type fakePodLogReader struct {
+	logs  string
+	calls []*corev1.PodLogOptions
+}
+
+func (f *fakePodLogReader) ReadLogs(_ context.Context, _, _ string, opts *corev1.PodLogOptions) ([]byte, error) {
+	f.calls = append(f.calls, opts)
+	return []byte(f.logs), nil
+}
+
+var _ = Describe("Failure logs", func() {
+	var (
+		reader *fakePodLogReader
+		r      *MinerReconciler
+		miner  *appsv1alpha1.Miner
+	)
+
+	BeforeEach(func() {
+		miner = &appsv1alpha1.Miner{
+			ObjectMeta: metav1.ObjectMeta{Name: "failed-miner", Namespace: "default"},
+			Spec: appsv1alpha1.MinerSpec{
+				ChainName:           "chain",
+				FailureLogTailLines: ptr.To(int32(3)),
+			},
+		}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: minerPodName(miner), Namespace: "default"},
+			Status:     corev1.PodStatus{Phase: corev1.PodFailed, Message: "Pod was evicted"},
+		}
+		reader = &fakePodLogReader{logs: "syncing block 41\nsyncing block 42\npanic: corrupted database\n"}
+		r = &MinerReconciler{
+			Client:    fake.NewClientBuilder().WithScheme(k8sClient.Scheme()).WithObjects(pod).Build(),
+			LogReader: reader,
+		}
+	})
+
+	It("should append the tail of the container logs to the failure message", func() {
+		Expect(r.syncPodStatus(ctx, miner, nil)).To(Succeed())
+
+		Expect(miner.Status.Phase).To(Equal(appsv1alpha1.MinerPhaseFailed))
+		Expect(miner.Status.FailureMessage).NotTo(BeNil())
+		Expect(*miner.Status.FailureMessage).To(Equal("Pod was evicted\nLast logs of container miner:\n" +
+			"syncing block 41\nsyncing block 42\npanic: corrupted database"))
+
+		Expect(reader.calls).To(HaveLen(1))
+		Expect(reader.calls[0].Container).To(Equal(minerContainerName))
+		Expect(reader.calls[0].TailLines).To(Equal(ptr.To(int64(3))))
+	})
+
+	It("should drop the oldest lines beyond the size cap", func() {
+		miner.Spec.FailureLogLimitBytes = ptr.To(int32(len("panic: corrupted database") + 5))
+		Expect(r.syncPodStatus(ctx, miner, nil)).To(Succeed())
+
+		Expect(*miner.Status.FailureMessage).To(HaveSuffix("container miner:\npanic: corrupted database"))
+		Expect(*miner.Status.FailureMessage).NotTo(ContainSubstring("syncing block"))
+	})
+
+	It("should keep the captured logs without reading them again", func() {
+		Expect(r.syncPodStatus(ctx, miner, nil)).To(Succeed())
+		message := *miner.Status.FailureMessage
+
+		Expect(r.syncPodStatus(ctx, miner, nil)).To(Succeed())
+		Expect(*miner.Status.FailureMessage).To(Equal(message))
+		Expect(reader.calls).To(HaveLen(1))
+	})
+
+	It("should not read logs unless the miner captures them", func() {
+		miner.Spec.FailureLogTailLines = nil
+		Expect(r.syncPodStatus(ctx, miner, nil)).To(Succeed())
+
+		Expect(*miner.Status.FailureMessage).To(Equal("Pod was evicted"))
+		Expect(reader.calls).To(BeEmpty())
+	})
+})
+
+var _ = Describe("tailBytes", func() {
+	It("should return logs within the limit unchanged", func() {
+		Expect(string(tailBytes([]byte("a\nb"), 10))).To(Equal("a\nb"))
+	})
+
+	It("should cut at a line boundary", func() {
+		Expect(string(tailBytes([]byte("first line\nsecond\nthird"), 10))).To(Equal("third"))
+	})
+
+	It("should keep the end of a single line longer than the limit", func() {
+		Expect(string(tailBytes([]byte(strings.Repeat("x", 20)), 5))).To(Equal("xxxxx"))
+	})
+})
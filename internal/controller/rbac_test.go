@@ -0,0 +1,90 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/yaml"
+)
+
+var _ = Describe("Manager role", func() {
+	const userName = "minerx-manager"
+
+	var managerClient client.Client
+
+	BeforeEach(func() {
+		By("Creating the ClusterRole generated from the RBAC markers")
+		raw, err := os.ReadFile(filepath.Join("..", "..", "config", "rbac", "role.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		role := &rbacv1.ClusterRole{}
+		Expect(yaml.UnmarshalStrict(raw, role)).To(Succeed())
+		role.Name = "test-" + role.Name
+		Expect(k8sClient.Create(ctx, role)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, role))).To(Succeed())
+		})
+
+		binding := &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: role.Name},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: role.Name},
+			Subjects:   []rbacv1.Subject{{APIGroup: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: userName}},
+		}
+		Expect(k8sClient.Create(ctx, binding)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, binding))).To(Succeed())
+		})
+
+		By("Authenticating as a user bound to the role")
+		user, err := testEnv.AddUser(envtest.User{Name: userName}, cfg)
+		Expect(err).NotTo(HaveOccurred())
+		managerClient, err = client.New(user.Config(), client.Options{Scheme: k8sClient.Scheme()})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should allow reading nodes for the miner topology", func() {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-rbac-node"}}
+		Expect(k8sClient.Create(ctx, node)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, node))).To(Succeed())
+		})
+
+		// The binding may take a moment to be picked up by the authorizer
+		Eventually(func() error {
+			return managerClient.Get(ctx, client.ObjectKeyFromObject(node), &corev1.Node{})
+		}).Should(Succeed())
+		Expect(managerClient.List(ctx, &corev1.NodeList{})).To(Succeed())
+	})
+
+	It("should not allow writing nodes", func() {
+		Eventually(func() error {
+			return managerClient.List(ctx, &corev1.NodeList{})
+		}).Should(Succeed())
+
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-rbac-forbidden-node"}}
+		Expect(errors.IsForbidden(managerClient.Create(ctx, node))).To(BeTrue())
+	})
+})
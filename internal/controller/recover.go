@@ -0,0 +1,43 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// recoverReconcilePanic recovers from a panic raised while reconciling req and turns it
+// into a retriable error, so that a single bad object cannot crash the manager.
+// It must be deferred directly by Reconcile with pointers to its named results.
+func recoverReconcilePanic(ctx context.Context, controllerName string, req ctrl.Request, result *ctrl.Result, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	reconcilePanicsTotal.WithLabelValues(controllerName).Inc()
+	log.FromContext(ctx).Error(fmt.Errorf("%v", r), "Recovered from panic during reconcile",
+		"controller", controllerName, "object", req.NamespacedName, "stacktrace", string(debug.Stack()))
+
+	*result = ctrl.Result{}
+	*err = fmt.Errorf("recovered from panic while reconciling %s: %v", req.NamespacedName, r)
+}
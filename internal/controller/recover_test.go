@@ -0,0 +1,81 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Reconcile panic recovery", func() {
+	ctx := context.Background()
+
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: "panicking", Namespace: "default"},
+	}
+
+	It("should recover from a panic in the Miner controller", func() {
+		before := testutil.ToFloat64(reconcilePanicsTotal.WithLabelValues("miner"))
+
+		controllerReconciler := &MinerReconciler{
+			Client: &panickingClient{Client: k8sClient},
+			Scheme: k8sClient.Scheme(),
+		}
+
+		var err error
+		Expect(func() {
+			_, err = controllerReconciler.Reconcile(ctx, request)
+		}).NotTo(Panic())
+		Expect(err).To(MatchError(ContainSubstring("recovered from panic")))
+		Expect(testutil.ToFloat64(reconcilePanicsTotal.WithLabelValues("miner"))).To(Equal(before + 1))
+	})
+
+	It("should recover from a panic in the Chain controller", func() {
+		controllerReconciler := &ChainReconciler{
+			Client: &panickingClient{Client: k8sClient},
+			Scheme: k8sClient.Scheme(),
+		}
+
+		_, err := controllerReconciler.Reconcile(ctx, request)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should recover from a panic in the MinerSet controller", func() {
+		controllerReconciler := &MinerSetReconciler{
+			Client: &panickingClient{Client: k8sClient},
+			Scheme: k8sClient.Scheme(),
+		}
+
+		_, err := controllerReconciler.Reconcile(ctx, request)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+// panickingClient is a client that panics on every Get.
+type panickingClient struct {
+	client.Client
+}
+
+func (c *panickingClient) Get(_ context.Context, key client.ObjectKey, _ client.Object, _ ...client.GetOption) error {
+	panic("injected panic getting " + key.String())
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// aggregateResults combines the results of several reconcile steps into the one that
+// requeues soonest. An immediate requeue wins over any RequeueAfter, a positive
+// RequeueAfter wins over no requeue at all, and among RequeueAfter values the smallest
+// wins. A RequeueAfter takes precedence over Requeue within a single result, as it does
+// in controller-runtime.
+func aggregateResults(results ...ctrl.Result) ctrl.Result {
+	var aggregate ctrl.Result
+	for _, result := range results {
+		switch {
+		case result.RequeueAfter > 0:
+			if aggregate.RequeueAfter == 0 || result.RequeueAfter < aggregate.RequeueAfter {
+				aggregate = ctrl.Result{RequeueAfter: result.RequeueAfter}
+			}
+		case result.Requeue:
+			return ctrl.Result{Requeue: true}
+		}
+	}
+	return aggregate
+}
+
+// resyncResult returns result, or a requeue after interval when result does not requeue.
+// Unlike aggregateResults, a result waiting longer than interval, such as a backoff, is
+// kept rather than cut short by the periodic resync.
+func resyncResult(result ctrl.Result, interval time.Duration) ctrl.Result {
+	if !result.IsZero() {
+		return result
+	}
+	return ctrl.Result{RequeueAfter: interval}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("aggregateResults", func() {
+	DescribeTable("should keep the result that requeues soonest",
+		func(results []ctrl.Result, expected ctrl.Result) {
+			Expect(aggregateResults(results...)).To(Equal(expected))
+		},
+		Entry("no results", nil, ctrl.Result{}),
+		Entry("only empty results", []ctrl.Result{{}, {}}, ctrl.Result{}),
+		Entry("smallest RequeueAfter",
+			[]ctrl.Result{{RequeueAfter: time.Minute}, {RequeueAfter: 5 * time.Second}, {RequeueAfter: 10 * time.Second}},
+			ctrl.Result{RequeueAfter: 5 * time.Second}),
+		Entry("RequeueAfter before an empty result",
+			[]ctrl.Result{{RequeueAfter: 5 * time.Second}, {}},
+			ctrl.Result{RequeueAfter: 5 * time.Second}),
+		Entry("RequeueAfter after an empty result",
+			[]ctrl.Result{{}, {RequeueAfter: 5 * time.Second}},
+			ctrl.Result{RequeueAfter: 5 * time.Second}),
+		Entry("immediate requeue over a RequeueAfter",
+			[]ctrl.Result{{RequeueAfter: 5 * time.Second}, {Requeue: true}},
+			ctrl.Result{Requeue: true}),
+		Entry("immediate requeue before a smaller RequeueAfter",
+			[]ctrl.Result{{Requeue: true}, {RequeueAfter: time.Second}},
+			ctrl.Result{Requeue: true}),
+		Entry("RequeueAfter taking precedence over Requeue in one result",
+			[]ctrl.Result{{Requeue: true, RequeueAfter: time.Minute}, {RequeueAfter: 5 * time.Second}},
+			ctrl.Result{RequeueAfter: 5 * time.Second}),
+	)
+})
+
+var _ = Describe("resyncResult", func() {
+	DescribeTable("should only resync a result that does not requeue",
+		func(result ctrl.Result, expected ctrl.Result) {
+			Expect(resyncResult(result, 10*time.Second)).To(Equal(expected))
+		},
+		Entry("empty result", ctrl.Result{}, ctrl.Result{RequeueAfter: 10 * time.Second}),
+		Entry("immediate requeue", ctrl.Result{Requeue: true}, ctrl.Result{Requeue: true}),
+		Entry("shorter RequeueAfter", ctrl.Result{RequeueAfter: 2 * time.Second}, ctrl.Result{RequeueAfter: 2 * time.Second}),
+		Entry("longer RequeueAfter", ctrl.Result{RequeueAfter: 5 * time.Minute}, ctrl.Result{RequeueAfter: 5 * time.Minute}),
+	)
+})
@@ -0,0 +1,48 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// updateStatusWithRetry writes the status of obj. A conflict with a concurrent write, for example
+// by a reconcile triggered through an owned object, refetches obj and reapplies the status
+// computed by this reconcile on top of it, instead of failing the whole reconcile. The
+// number of attempts is bounded by retry.DefaultRetry.
+func updateStatusWithRetry(ctx context.Context, c client.Client, obj client.Object) error {
+	attempt := 0
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		attempt++
+		if attempt > 1 {
+			// The status subresource only writes the status, so picking up the latest
+			// resource version is enough to reapply it
+			latest := obj.DeepCopyObject().(client.Object)
+			if err := c.Get(ctx, client.ObjectKeyFromObject(obj), latest); err != nil {
+				return err
+			}
+			log.FromContext(ctx).V(1).Info("Retrying status update after a conflict",
+				"object", client.ObjectKeyFromObject(obj), "attempt", attempt)
+			obj.SetResourceVersion(latest.GetResourceVersion())
+		}
+		return c.Status().Update(ctx, obj)
+	})
+}
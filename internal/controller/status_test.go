@@ -0,0 +1,112 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1alpha1 "github.com/ashwinyue/minerx/api/v1alpha1"
+)
+
+var _ = Describe("updateStatusWithRetry", func() {
+	ctx := context.Background()
+
+	var miner *appsv1alpha1.Miner
+
+	BeforeEach(func() {
+		miner = &appsv1alpha1.Miner{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "conflicting-miner",
+				Namespace: "default",
+			},
+			Spec: appsv1alpha1.MinerSpec{
+				ChainName:     "test-chain",
+				MinerType:     appsv1alpha1.MinerTypeSmall,
+				RestartPolicy: corev1.RestartPolicyAlways,
+			},
+		}
+		Expect(k8sClient.Create(ctx, miner)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, miner))).To(Succeed())
+		})
+	})
+
+	It("should reapply the status after a conflict", func() {
+		c := &conflictingStatusClient{Client: k8sClient}
+		miner.Status.Phase = appsv1alpha1.MinerPhaseRunning
+		Expect(updateStatusWithRetry(ctx, c, miner)).To(Succeed())
+		Expect(c.attempts).To(Equal(2))
+
+		updated := &appsv1alpha1.Miner{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(miner), updated)).To(Succeed())
+		Expect(updated.Status.Phase).To(Equal(appsv1alpha1.MinerPhaseRunning))
+	})
+
+	It("should let a reconcile succeed despite a conflicting status update", func() {
+		c := &conflictingStatusClient{Client: k8sClient}
+		controllerReconciler := &MinerReconciler{
+			Client:            c,
+			Scheme:            k8sClient.Scheme(),
+			DisableFinalizers: true,
+		}
+
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(miner),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.attempts).To(BeNumerically(">=", 2))
+
+		updated := &appsv1alpha1.Miner{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(miner), updated)).To(Succeed())
+		Expect(updated.Status.Phase).NotTo(BeEmpty())
+	})
+})
+
+// conflictingStatusClient is a client whose first status update fails with a conflict,
+// simulating a concurrent write to the object.
+type conflictingStatusClient struct {
+	client.Client
+	attempts int
+}
+
+func (c *conflictingStatusClient) Status() client.SubResourceWriter {
+	return &conflictingStatusWriter{SubResourceWriter: c.Client.Status(), client: c}
+}
+
+type conflictingStatusWriter struct {
+	client.SubResourceWriter
+	client *conflictingStatusClient
+}
+
+func (w *conflictingStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	w.client.attempts++
+	if w.client.attempts == 1 {
+		return errors.NewConflict(appsv1alpha1.GroupVersion.WithResource("miners").GroupResource(), obj.GetName(),
+			fmt.Errorf("injected conflict"))
+	}
+	return w.SubResourceWriter.Update(ctx, obj, opts...)
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+
+	appsv1alpha1 "github.com/ashwinyue/minerx/api/v1alpha1"
+)
+
+// templateHashAnnotation is stamped on miners created by a MinerSet with the hash of the
+// template they were created from.
+const templateHashAnnotation = "minerset.onex.io/template-hash"
+
+// TemplateHash returns a stable hash of the miner template. The template is serialized
+// to JSON, which orders map keys, so equal templates hash equal across process restarts.
+func TemplateHash(template appsv1alpha1.MinerTemplateSpec) (string, error) {
+	data, err := json.Marshal(template)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize the miner template: %w", err)
+	}
+
+	hasher := fnv.New32a()
+	_, _ = hasher.Write(data)
+	return strconv.FormatUint(uint64(hasher.Sum32()), 36), nil
+}
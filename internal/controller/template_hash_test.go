@@ -0,0 +1,119 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	appsv1alpha1 "github.com/ashwinyue/minerx/api/v1alpha1"
+)
+
+// mustTemplateHash returns the hash of the miner template, failing the test on error.
+func mustTemplateHash(template appsv1alpha1.MinerTemplateSpec) string {
+	hash, err := TemplateHash(template)
+	Expect(err).NotTo(HaveOccurred())
+	return hash
+}
+
+var _ = Describe("TemplateHash", func() {
+	newTemplate := func() appsv1alpha1.MinerTemplateSpec {
+		return appsv1alpha1.MinerTemplateSpec{
+			ObjectMeta: appsv1alpha1.ObjectMeta{
+				Labels: map[string]string{"app": "miner", "tier": "worker"},
+			},
+			Spec: appsv1alpha1.MinerSpec{
+				ChainName:     "chain",
+				MinerType:     appsv1alpha1.MinerTypeSmall,
+				RestartPolicy: corev1.RestartPolicyAlways,
+			},
+		}
+	}
+
+	It("should hash equal templates equal", func() {
+		Expect(mustTemplateHash(newTemplate())).To(Equal(mustTemplateHash(newTemplate())))
+	})
+
+	It("should not depend on map insertion order", func() {
+		template := newTemplate()
+		template.Labels = map[string]string{"tier": "worker", "app": "miner"}
+		Expect(mustTemplateHash(template)).To(Equal(mustTemplateHash(newTemplate())))
+	})
+
+	It("should change when a field changes", func() {
+		base := mustTemplateHash(newTemplate())
+
+		template := newTemplate()
+		template.Spec.MinerType = appsv1alpha1.MinerTypeLarge
+		Expect(mustTemplateHash(template)).NotTo(Equal(base))
+
+		template = newTemplate()
+		template.Labels["tier"] = "canary"
+		Expect(mustTemplateHash(template)).NotTo(Equal(base))
+	})
+})
+
+var _ = Describe("computeDesiredMiner", func() {
+	It("should stamp the template hash on the miner", func() {
+		ms := &appsv1alpha1.MinerSet{
+			Spec: appsv1alpha1.MinerSetSpec{
+				Template: appsv1alpha1.MinerTemplateSpec{
+					Spec: appsv1alpha1.MinerSpec{ChainName: "chain", MinerType: appsv1alpha1.MinerTypeSmall},
+				},
+			},
+		}
+		ms.Name = "minerset"
+
+		miner := (&MinerSetReconciler{}).computeDesiredMiner(ms, nil, mustTemplateHash(ms.Spec.Template))
+		Expect(miner.Annotations).To(HaveKeyWithValue(templateHashAnnotation, mustTemplateHash(ms.Spec.Template)))
+	})
+
+	It("should not let the template override the controller labels and annotations", func() {
+		ms := &appsv1alpha1.MinerSet{
+			Spec: appsv1alpha1.MinerSetSpec{
+				Template: appsv1alpha1.MinerTemplateSpec{
+					ObjectMeta: appsv1alpha1.ObjectMeta{
+						Labels: map[string]string{
+							minerSetNameLabel: "other-minerset",
+							chainNameLabel:    "other-chain",
+							"app":             "miner",
+						},
+						Annotations: map[string]string{
+							templateHashAnnotation: "stale",
+							minerSetNameLabel:      "other-minerset",
+							"example.com/team":     "mining",
+						},
+					},
+					Spec: appsv1alpha1.MinerSpec{ChainName: "chain", MinerType: appsv1alpha1.MinerTypeSmall},
+				},
+			},
+		}
+		ms.Name = "minerset"
+
+		miner := (&MinerSetReconciler{}).computeDesiredMiner(ms, nil, mustTemplateHash(ms.Spec.Template))
+		Expect(miner.Labels).To(Equal(map[string]string{
+			minerSetNameLabel: "minerset",
+			chainNameLabel:    "chain",
+			"app":             "miner",
+		}))
+		Expect(miner.Annotations).To(Equal(map[string]string{
+			templateHashAnnotation: mustTemplateHash(ms.Spec.Template),
+			"example.com/team":     "mining",
+		}))
+	})
+})
@@ -0,0 +1,153 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	appsv1alpha1 "github.com/ashwinyue/minerx/api/v1alpha1"
+	"github.com/ashwinyue/minerx/internal/controller"
+)
+
+// log is for logging in this package.
+var minerlog = logf.Log.WithName("miner-resource")
+
+// SetupMinerWebhookWithManager registers the conversion, defaulting and validating webhooks for Miner in the manager.
+func SetupMinerWebhookWithManager(mgr ctrl.Manager, resourceProfiles map[appsv1alpha1.MinerType]corev1.ResourceRequirements) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&appsv1alpha1.Miner{}).
+		WithDefaulter(&MinerCustomDefaulter{ResourceProfiles: resourceProfiles}).
+		WithValidator(&MinerCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-apps-onex-io-v1alpha1-miner,mutating=true,failurePolicy=fail,sideEffects=None,groups=apps.onex.io,resources=miners,verbs=create;update,versions=v1alpha1,name=mminer-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// MinerCustomDefaulter struct is responsible for setting default values on the custom resource of the
+// Kind Miner when those are created.
+type MinerCustomDefaulter struct {
+	// ResourceProfiles are the compute resources set on a Miner that does not specify any,
+	// by miner type. Miners of a type without a profile are left unchanged.
+	ResourceProfiles map[appsv1alpha1.MinerType]corev1.ResourceRequirements
+}
+
+var _ webhook.CustomDefaulter = &MinerCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind Miner.
+func (d *MinerCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	miner, ok := obj.(*appsv1alpha1.Miner)
+	if !ok {
+		return fmt.Errorf("expected a Miner object but got %T", obj)
+	}
+
+	// Defaults only apply to a new miner. A field cleared by an update must stay cleared,
+	// setting it again would change the spec and recreate the pod.
+	if req, err := admission.RequestFromContext(ctx); err == nil && req.Operation == admissionv1.Update {
+		return nil
+	}
+	minerlog.Info("Defaulting for Miner", "name", miner.GetName())
+
+	if miner.Spec.PodDeletionTimeout == nil {
+		miner.Spec.PodDeletionTimeout = &metav1.Duration{Duration: appsv1alpha1.DefaultMinerPodDeletionTimeout}
+	}
+
+	if equality.Semantic.DeepEqual(miner.Spec.Resources, corev1.ResourceRequirements{}) {
+		if profile, ok := d.ResourceProfiles[miner.Spec.MinerType]; ok {
+			miner.Spec.Resources = *profile.DeepCopy()
+		}
+	}
+
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-apps-onex-io-v1alpha1-miner,mutating=false,failurePolicy=fail,sideEffects=None,groups=apps.onex.io,resources=miners,verbs=create;update,versions=v1alpha1,name=vminer-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// MinerCustomValidator struct is responsible for validating the Miner resource
+// when it is created, updated, or deleted.
+type MinerCustomValidator struct{}
+
+var _ webhook.CustomValidator = &MinerCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Miner.
+func (v *MinerCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	miner, ok := obj.(*appsv1alpha1.Miner)
+	if !ok {
+		return nil, fmt.Errorf("expected a Miner object but got %T", obj)
+	}
+	minerlog.Info("Validation for Miner upon creation", "name", miner.GetName())
+
+	return nil, validateMiner(miner)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Miner.
+func (v *MinerCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	miner, ok := newObj.(*appsv1alpha1.Miner)
+	if !ok {
+		return nil, fmt.Errorf("expected a Miner object for the newObj but got %T", newObj)
+	}
+	minerlog.Info("Validation for Miner upon update", "name", miner.GetName())
+
+	return nil, validateMiner(miner)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type Miner.
+func (v *MinerCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateMiner validates a created or updated Miner.
+func validateMiner(miner *appsv1alpha1.Miner) error {
+	allErrs := validateContainerNames(&miner.Spec, field.NewPath("spec"))
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(appsv1alpha1.GroupVersion.WithKind("Miner").GroupKind(), miner.Name, allErrs)
+}
+
+// validateContainerNames checks that the sidecars and init containers of the miner spec at
+// fldPath are named uniquely within the pod, including the containers the controller adds to it.
+func validateContainerNames(spec *appsv1alpha1.MinerSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := controller.MinerPodContainerNames(spec)
+	for i, sidecar := range spec.Sidecars {
+		if names.Has(sidecar.Name) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("sidecars").Index(i).Child("name"), sidecar.Name))
+			continue
+		}
+		names.Insert(sidecar.Name)
+	}
+	for i, initContainer := range spec.InitContainers {
+		if names.Has(initContainer.Name) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("initContainers").Index(i).Child("name"), initContainer.Name))
+			continue
+		}
+		names.Insert(initContainer.Name)
+	}
+	return allErrs
+}
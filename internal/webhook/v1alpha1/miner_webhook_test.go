@@ -0,0 +1,182 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	appsv1alpha1 "github.com/ashwinyue/minerx/api/v1alpha1"
+)
+
+var _ = Describe("Miner Webhook", func() {
+	var (
+		obj       *appsv1alpha1.Miner
+		defaulter MinerCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		obj = &appsv1alpha1.Miner{}
+		defaulter = MinerCustomDefaulter{}
+	})
+
+	Context("When defaulting PodDeletionTimeout", func() {
+		It("Should default PodDeletionTimeout when it is nil", func() {
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.PodDeletionTimeout).NotTo(BeNil())
+			Expect(obj.Spec.PodDeletionTimeout.Duration).To(Equal(10 * time.Second))
+		})
+
+		It("Should preserve an explicitly set PodDeletionTimeout", func() {
+			obj.Spec.PodDeletionTimeout = &metav1.Duration{Duration: time.Minute}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.PodDeletionTimeout.Duration).To(Equal(time.Minute))
+		})
+
+		It("Should preserve an explicit zero PodDeletionTimeout", func() {
+			obj.Spec.PodDeletionTimeout = &metav1.Duration{}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.PodDeletionTimeout.Duration).To(BeZero())
+		})
+	})
+
+	Context("When defaulting an updated Miner", func() {
+		var updateCtx context.Context
+
+		BeforeEach(func() {
+			defaulter.ResourceProfiles = appsv1alpha1.DefaultMinerResourceProfiles()
+			updateCtx = admission.NewContextWithRequest(ctx, admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Update},
+			})
+		})
+
+		It("Should leave explicitly cleared fields alone", func() {
+			obj.Spec.MinerType = appsv1alpha1.MinerTypeSmall
+			Expect(defaulter.Default(updateCtx, obj)).To(Succeed())
+			Expect(obj.Spec.PodDeletionTimeout).To(BeNil())
+			Expect(obj.Spec.Resources).To(Equal(corev1.ResourceRequirements{}))
+		})
+
+		It("Should still default a created Miner", func() {
+			createCtx := admission.NewContextWithRequest(ctx, admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Create},
+			})
+			obj.Spec.MinerType = appsv1alpha1.MinerTypeSmall
+			Expect(defaulter.Default(createCtx, obj)).To(Succeed())
+			Expect(obj.Spec.PodDeletionTimeout).NotTo(BeNil())
+			Expect(obj.Spec.Resources.Requests).NotTo(BeEmpty())
+		})
+	})
+
+	Context("When defaulting Resources", func() {
+		BeforeEach(func() {
+			defaulter.ResourceProfiles = appsv1alpha1.DefaultMinerResourceProfiles()
+		})
+
+		DescribeTable("Should inject the resource profile of the miner type when resources are empty",
+			func(minerType appsv1alpha1.MinerType, requestCPU, limitMemory string) {
+				obj.Spec.MinerType = minerType
+				Expect(defaulter.Default(ctx, obj)).To(Succeed())
+				Expect(obj.Spec.Resources.Requests.Cpu().Equal(resource.MustParse(requestCPU))).To(BeTrue())
+				Expect(obj.Spec.Resources.Limits.Memory().Equal(resource.MustParse(limitMemory))).To(BeTrue())
+			},
+			Entry("small", appsv1alpha1.MinerTypeSmall, "100m", "512Mi"),
+			Entry("medium", appsv1alpha1.MinerTypeMedium, "500m", "1Gi"),
+			Entry("large", appsv1alpha1.MinerTypeLarge, "1", "4Gi"),
+		)
+
+		It("Should produce a spec semantically equal to the spec before defaulting", func() {
+			obj.Spec.MinerType = appsv1alpha1.MinerTypeMedium
+			original := obj.Spec.DeepCopy()
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Resources).NotTo(Equal(original.Resources))
+			Expect(appsv1alpha1.MinerSpecSemanticEqual(*original, obj.Spec)).To(BeTrue())
+		})
+
+		It("Should preserve explicitly set resources", func() {
+			obj.Spec.MinerType = appsv1alpha1.MinerTypeLarge
+			obj.Spec.Resources = corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+			}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Resources).To(Equal(corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+			}))
+		})
+
+		It("Should not share the profile between miners", func() {
+			obj.Spec.MinerType = appsv1alpha1.MinerTypeSmall
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			obj.Spec.Resources.Requests[corev1.ResourceCPU] = resource.MustParse("2")
+			profile := defaulter.ResourceProfiles[appsv1alpha1.MinerTypeSmall]
+			Expect(profile.Requests.Cpu().String()).To(Equal("100m"))
+		})
+	})
+
+	Context("When validating container names", func() {
+		var validator MinerCustomValidator
+
+		BeforeEach(func() {
+			obj.Spec.Sidecars = []corev1.Container{{Name: "exporter", Image: "exporter:latest"}}
+		})
+
+		DescribeTable("Should deny an init container named like another container of the pod",
+			func(name string) {
+				obj.Spec.InitContainers = []corev1.Container{{Name: name, Image: "busybox"}}
+				_, err := validator.ValidateCreate(ctx, obj)
+				Expect(err).To(MatchError(ContainSubstring("spec.initContainers[0].name: Duplicate value")))
+			},
+			Entry("the miner container", "miner"),
+			Entry("a sidecar", "exporter"),
+			Entry("the chain config init container", "chain-config"),
+		)
+
+		DescribeTable("Should deny a sidecar named like another container of the pod",
+			func(name string) {
+				obj.Spec.Sidecars = append(obj.Spec.Sidecars, corev1.Container{Name: name, Image: "busybox"})
+				_, err := validator.ValidateCreate(ctx, obj)
+				Expect(err).To(MatchError(ContainSubstring("spec.sidecars[1].name: Duplicate value")))
+			},
+			Entry("the miner container", "miner"),
+			Entry("another sidecar", "exporter"),
+			Entry("the chain config init container", "chain-config"),
+		)
+
+		It("Should deny init containers sharing a name", func() {
+			obj.Spec.InitContainers = []corev1.Container{
+				{Name: "download", Image: "busybox"},
+				{Name: "download", Image: "busybox"},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.initContainers[1].name: Duplicate value")))
+		})
+
+		It("Should admit uniquely named init containers", func() {
+			obj.Spec.InitContainers = []corev1.Container{{Name: "download", Image: "busybox"}}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+			Expect(validator.ValidateUpdate(ctx, obj.DeepCopy(), obj)).Error().NotTo(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,183 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	appsv1alpha1 "github.com/ashwinyue/minerx/api/v1alpha1"
+	"github.com/ashwinyue/minerx/internal/controller"
+)
+
+// log is for logging in this package.
+var minersetlog = logf.Log.WithName("minerset-resource")
+
+// SetupMinerSetWebhookWithManager registers the webhook for MinerSet in the manager.
+func SetupMinerSetWebhookWithManager(mgr ctrl.Manager, defaultReplicas int32) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&appsv1alpha1.MinerSet{}).
+		WithDefaulter(&MinerSetCustomDefaulter{DefaultReplicas: defaultReplicas}).
+		WithValidator(&MinerSetCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-apps-onex-io-v1alpha1-minerset,mutating=true,failurePolicy=fail,sideEffects=None,groups=apps.onex.io,resources=minersets,verbs=create;update,versions=v1alpha1,name=mminerset-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// MinerSetCustomDefaulter struct is responsible for setting default values on the custom resource of the
+// Kind MinerSet when those are created or updated.
+type MinerSetCustomDefaulter struct {
+	// DefaultReplicas is the number of replicas set on a MinerSet that does not specify any.
+	DefaultReplicas int32
+}
+
+var _ webhook.CustomDefaulter = &MinerSetCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind MinerSet.
+func (d *MinerSetCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	minerset, ok := obj.(*appsv1alpha1.MinerSet)
+	if !ok {
+		return fmt.Errorf("expected an MinerSet object but got %T", obj)
+	}
+	minersetlog.Info("Defaulting for MinerSet", "name", minerset.GetName())
+
+	if minerset.Spec.Replicas == nil {
+		replicas := d.DefaultReplicas
+		minerset.Spec.Replicas = &replicas
+	}
+
+	if minerset.Spec.ProgressDeadlineSeconds == nil {
+		deadline := appsv1alpha1.DefaultMinerSetProgressDeadlineSeconds
+		minerset.Spec.ProgressDeadlineSeconds = &deadline
+	}
+
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-apps-onex-io-v1alpha1-minerset,mutating=false,failurePolicy=fail,sideEffects=None,groups=apps.onex.io,resources=minersets,verbs=create;update,versions=v1alpha1,name=vminerset-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// MinerSetCustomValidator struct is responsible for validating the MinerSet resource
+// when it is created, updated, or deleted.
+type MinerSetCustomValidator struct{}
+
+var _ webhook.CustomValidator = &MinerSetCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type MinerSet.
+func (v *MinerSetCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	minerset, ok := obj.(*appsv1alpha1.MinerSet)
+	if !ok {
+		return nil, fmt.Errorf("expected a MinerSet object but got %T", obj)
+	}
+	minersetlog.Info("Validation for MinerSet upon creation", "name", minerset.GetName())
+
+	return nil, validateMinerSet(minerset)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type MinerSet.
+func (v *MinerSetCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldMinerSet, ok := oldObj.(*appsv1alpha1.MinerSet)
+	if !ok {
+		return nil, fmt.Errorf("expected a MinerSet object for the oldObj but got %T", oldObj)
+	}
+	minerset, ok := newObj.(*appsv1alpha1.MinerSet)
+	if !ok {
+		return nil, fmt.Errorf("expected a MinerSet object for the newObj but got %T", newObj)
+	}
+	minersetlog.Info("Validation for MinerSet upon update", "name", minerset.GetName())
+
+	return nil, validateMinerSetUpdate(oldMinerSet, minerset)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type MinerSet.
+func (v *MinerSetCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateMinerSet validates a created MinerSet. Its template labels must match its
+// selector: miners created from a template the selector does not match are never counted
+// as replicas, so the MinerSet would create new ones forever.
+func validateMinerSet(minerset *appsv1alpha1.MinerSet) error {
+	allErrs := validateMinerSetSpec(minerset)
+	if err := validateTemplateMatchesSelector(minerset); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	return toInvalidError(minerset, allErrs)
+}
+
+// validateMinerSetUpdate validates the updated MinerSet like validateMinerSet, except that
+// the template labels are only checked against the selector when either of them changes,
+// so that MinerSets created before this check existed can still be updated.
+func validateMinerSetUpdate(oldMinerSet, minerset *appsv1alpha1.MinerSet) error {
+	allErrs := validateMinerSetSpec(minerset)
+	if !equality.Semantic.DeepEqual(oldMinerSet.Spec.Selector, minerset.Spec.Selector) ||
+		!equality.Semantic.DeepEqual(controller.MinerSetMinerLabels(oldMinerSet), controller.MinerSetMinerLabels(minerset)) {
+		if err := validateTemplateMatchesSelector(minerset); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	return toInvalidError(minerset, allErrs)
+}
+
+// validateTemplateMatchesSelector returns an error if the labels the controller sets on the
+// miners of the MinerSet, which derive from its template labels, do not match its selector.
+func validateTemplateMatchesSelector(minerset *appsv1alpha1.MinerSet) *field.Error {
+	// An invalid selector is reported on the MinerSet status by the controller
+	selector, err := metav1.LabelSelectorAsSelector(&minerset.Spec.Selector)
+	if err != nil {
+		return nil
+	}
+	minerLabels := labels.Set(controller.MinerSetMinerLabels(minerset))
+	if selector.Matches(minerLabels) {
+		return nil
+	}
+	return field.Invalid(field.NewPath("spec", "template", "metadata", "labels"), minerset.Spec.Template.Labels,
+		fmt.Sprintf("must match the selector %q, the miners would be labeled %q", selector.String(), minerLabels.String()))
+}
+
+// validateMinerSetSpec checks that Replicas is set explicitly and is not negative, and that
+// the template init containers are named uniquely. The defaulting webhook runs first, so an
+// unset Replicas only reaches this point when defaulting was skipped.
+func validateMinerSetSpec(minerset *appsv1alpha1.MinerSet) field.ErrorList {
+	var allErrs field.ErrorList
+	replicasPath := field.NewPath("spec", "replicas")
+	if minerset.Spec.Replicas == nil {
+		allErrs = append(allErrs, field.Required(replicasPath, "must be set, use 0 to scale to no miners"))
+	} else if *minerset.Spec.Replicas < 0 {
+		allErrs = append(allErrs, field.Invalid(replicasPath, *minerset.Spec.Replicas, "must be greater than or equal to 0"))
+	}
+
+	allErrs = append(allErrs, validateContainerNames(&minerset.Spec.Template.Spec, field.NewPath("spec", "template", "spec"))...)
+	return allErrs
+}
+
+// toInvalidError returns an Invalid error for the MinerSet, or nil if there are no errors.
+func toInvalidError(minerset *appsv1alpha1.MinerSet, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(appsv1alpha1.GroupVersion.WithKind("MinerSet").GroupKind(), minerset.Name, allErrs)
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	appsv1alpha1 "github.com/ashwinyue/minerx/api/v1alpha1"
+)
+
+var _ = Describe("MinerSet Webhook", func() {
+	var (
+		obj       *appsv1alpha1.MinerSet
+		defaulter MinerSetCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		obj = &appsv1alpha1.MinerSet{}
+		defaulter = MinerSetCustomDefaulter{DefaultReplicas: appsv1alpha1.DefaultMinerSetReplicas}
+	})
+
+	Context("When creating MinerSet under Defaulting Webhook", func() {
+		It("Should default Replicas when it is nil", func() {
+			By("calling the Default method with Replicas unset")
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+
+			By("checking that the default value is set")
+			Expect(obj.Spec.Replicas).NotTo(BeNil())
+			Expect(*obj.Spec.Replicas).To(Equal(appsv1alpha1.DefaultMinerSetReplicas))
+		})
+
+		It("Should preserve an explicitly set Replicas", func() {
+			By("calling the Default method with Replicas set")
+			replicas := int32(5)
+			obj.Spec.Replicas = &replicas
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+
+			By("checking that the existing value is preserved")
+			Expect(*obj.Spec.Replicas).To(Equal(int32(5)))
+		})
+
+		It("Should preserve an explicit zero Replicas", func() {
+			replicas := int32(0)
+			obj.Spec.Replicas = &replicas
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(*obj.Spec.Replicas).To(Equal(int32(0)))
+		})
+	})
+
+	Context("When defaulting ProgressDeadlineSeconds", func() {
+		It("Should default ProgressDeadlineSeconds when it is nil", func() {
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.ProgressDeadlineSeconds).NotTo(BeNil())
+			Expect(*obj.Spec.ProgressDeadlineSeconds).To(Equal(appsv1alpha1.DefaultMinerSetProgressDeadlineSeconds))
+		})
+
+		It("Should preserve an explicitly set ProgressDeadlineSeconds", func() {
+			obj.Spec.ProgressDeadlineSeconds = ptr.To(int32(120))
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(*obj.Spec.ProgressDeadlineSeconds).To(Equal(int32(120)))
+		})
+	})
+
+	Context("When creating or updating MinerSet under Validating Webhook", func() {
+		var validator MinerSetCustomValidator
+
+		It("Should deny creation when Replicas is unset", func() {
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.replicas: Required value")))
+		})
+
+		It("Should deny creation when Replicas is negative", func() {
+			obj.Spec.Replicas = ptr.To(int32(-1))
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.replicas: Invalid value")))
+		})
+
+		It("Should admit creation when Replicas is zero", func() {
+			obj.Spec.Replicas = ptr.To(int32(0))
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should validate updates the same way", func() {
+			oldObj := obj.DeepCopy()
+			oldObj.Spec.Replicas = ptr.To(int32(1))
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(HaveOccurred())
+
+			obj.Spec.Replicas = ptr.To(int32(3))
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny a template init container named like the miner container", func() {
+			obj.Spec.Replicas = ptr.To(int32(1))
+			obj.Spec.Template.Spec.InitContainers = []corev1.Container{{Name: "miner", Image: "busybox"}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.template.spec.initContainers[0].name: Duplicate value")))
+		})
+
+		Context("When the selector and the template labels are created", func() {
+			BeforeEach(func() {
+				obj.Spec.Replicas = ptr.To(int32(3))
+				obj.Spec.Selector = metav1.LabelSelector{MatchLabels: map[string]string{"app": "miner"}}
+			})
+
+			It("Should admit a template whose labels match the selector", func() {
+				obj.Spec.Template.Labels = map[string]string{"app": "miner", "team": "mining"}
+				Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+			})
+
+			It("Should deny a template sharing no label with the selector", func() {
+				obj.Spec.Template.Labels = map[string]string{"team": "mining"}
+				_, err := validator.ValidateCreate(ctx, obj)
+				Expect(err).To(MatchError(ContainSubstring("spec.template.metadata.labels: Invalid value")))
+				Expect(err).To(MatchError(ContainSubstring(`must match the selector "app=miner"`)))
+			})
+
+			It("Should deny a template whose label value differs from the selector", func() {
+				obj.Spec.Template.Labels = map[string]string{"app": "other"}
+				_, err := validator.ValidateCreate(ctx, obj)
+				Expect(err).To(MatchError(ContainSubstring("must match the selector")))
+			})
+
+			It("Should deny a selector on the name of another MinerSet", func() {
+				obj.Name = "workers"
+				obj.Spec.Selector = metav1.LabelSelector{MatchLabels: map[string]string{"minerset.onex.io/name": "other"}}
+				obj.Spec.Template.Labels = map[string]string{"minerset.onex.io/name": "other"}
+				_, err := validator.ValidateCreate(ctx, obj)
+				Expect(err).To(MatchError(ContainSubstring("must match the selector")))
+			})
+
+			It("Should admit a selector on the labels set by the controller", func() {
+				obj.Name = "workers"
+				obj.Spec.Template.Spec.ChainName = "chain"
+				obj.Spec.Selector = metav1.LabelSelector{MatchLabels: map[string]string{
+					"minerset.onex.io/name": "workers",
+					"chain.onex.io/name":    "chain",
+				}}
+				obj.Spec.Template.Labels = nil
+				Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+			})
+		})
+
+		Context("When changing the selector or the template labels", func() {
+			var oldObj *appsv1alpha1.MinerSet
+
+			BeforeEach(func() {
+				obj.Spec.Replicas = ptr.To(int32(3))
+				obj.Spec.Selector = metav1.LabelSelector{MatchLabels: map[string]string{"app": "miner"}}
+				obj.Spec.Template.Labels = map[string]string{"app": "miner"}
+				oldObj = obj.DeepCopy()
+			})
+
+			It("Should admit adding a label", func() {
+				obj.Spec.Template.Labels["team"] = "mining"
+				Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().NotTo(HaveOccurred())
+			})
+
+			It("Should deny removing a label the selector requires", func() {
+				delete(obj.Spec.Template.Labels, "app")
+				_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+				Expect(err).To(MatchError(ContainSubstring("spec.template.metadata.labels: Invalid value")))
+				Expect(err).To(MatchError(ContainSubstring("must match the selector")))
+			})
+
+			It("Should deny a selector that no longer matches the template", func() {
+				obj.Spec.Selector = metav1.LabelSelector{MatchLabels: map[string]string{"app": "worker"}}
+				_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+				Expect(err).To(MatchError(ContainSubstring(`must match the selector "app=worker"`)))
+			})
+
+			It("Should admit a selector that still matches the template", func() {
+				obj.Spec.Selector = metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      "app",
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   []string{"miner", "worker"},
+				}}}
+				Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().NotTo(HaveOccurred())
+			})
+
+			It("Should admit other updates of a template that already did not match", func() {
+				oldObj.Spec.Template.Labels = nil
+				obj.Spec.Template.Labels = nil
+				obj.Spec.Replicas = ptr.To(int32(5))
+				Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().NotTo(HaveOccurred())
+			})
+		})
+	})
+})
@@ -63,3 +63,24 @@ func IsUnknown(from Getter, conditionType ConditionType) bool {
 	}
 	return true
 }
+
+// GetTransitionTime returns the last transition time of the condition with the given type,
+// or nil if the condition does not exist.
+func GetTransitionTime(from Getter, conditionType ConditionType) *metav1.Time {
+	if c := Get(from, conditionType); c != nil {
+		return &c.LastTransitionTime
+	}
+	return nil
+}
+
+// GetLastTransitionTime returns the most recent last transition time across all
+// conditions, or nil if there are no conditions.
+func GetLastTransitionTime(from Getter) *metav1.Time {
+	var latest *metav1.Time
+	for _, c := range from.GetConditions() {
+		if latest == nil || latest.Before(&c.LastTransitionTime) {
+			latest = c.LastTransitionTime.DeepCopy()
+		}
+	}
+	return latest
+}
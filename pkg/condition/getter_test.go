@@ -0,0 +1,67 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package condition
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeObject is a minimal Setter used by the tests of this package.
+type fakeObject struct {
+	conditions []metav1.Condition
+}
+
+func (o *fakeObject) GetConditions() []metav1.Condition {
+	return o.conditions
+}
+
+func (o *fakeObject) SetConditions(conditions []metav1.Condition) {
+	o.conditions = conditions
+}
+
+func TestGetTransitionTime(t *testing.T) {
+	g := NewWithT(t)
+
+	now := metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	obj := &fakeObject{conditions: []metav1.Condition{
+		{Type: string(ReadyCondition), Status: metav1.ConditionTrue, LastTransitionTime: now},
+	}}
+
+	g.Expect(GetTransitionTime(obj, ReadyCondition)).To(Equal(&now))
+	g.Expect(GetTransitionTime(obj, MinersReadyCondition)).To(BeNil())
+}
+
+func TestGetLastTransitionTime(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(GetLastTransitionTime(&fakeObject{})).To(BeNil())
+
+	oldest := metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	latest := metav1.NewTime(oldest.Add(2 * time.Hour))
+	middle := metav1.NewTime(oldest.Add(time.Hour))
+	obj := &fakeObject{conditions: []metav1.Condition{
+		{Type: string(MinersCreatedCondition), Status: metav1.ConditionTrue, LastTransitionTime: middle},
+		{Type: string(MinersReadyCondition), Status: metav1.ConditionFalse, LastTransitionTime: latest},
+		{Type: string(ResizedCondition), Status: metav1.ConditionTrue, LastTransitionTime: oldest},
+	}}
+
+	g.Expect(GetLastTransitionTime(obj)).To(Equal(&latest))
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package condition
 
 import (
+	"fmt"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -36,6 +38,16 @@ func SetFalse(to Setter, conditionType ConditionType, reason ConditionReason, me
 	Set(to, FalseCondition(conditionType, reason, message))
 }
 
+// MarkTrue is used to set a condition to True.
+func MarkTrue(to Setter, conditionType ConditionType) {
+	SetTrue(to, conditionType)
+}
+
+// MarkFalsef is used to set a condition to False with a printf-style message.
+func MarkFalsef(to Setter, conditionType ConditionType, reason ConditionReason, format string, args ...any) {
+	SetFalse(to, conditionType, reason, fmt.Sprintf(format, args...))
+}
+
 // SetUnknown is used to set a condition to Unknown.
 func SetUnknown(to Setter, conditionType ConditionType, reason, message string) {
 	Set(to, UnknownCondition(conditionType, reason, message))
@@ -47,6 +59,17 @@ func Set(to Setter, condition metav1.Condition) {
 	setCondition(to, conditions, condition)
 }
 
+// Delete removes the condition with the given type. It is a no-op if the condition does not exist.
+func Delete(to Setter, conditionType ConditionType) {
+	conditions := to.GetConditions()
+	for i := range conditions {
+		if conditions[i].Type == string(conditionType) {
+			to.SetConditions(append(conditions[:i:i], conditions[i+1:]...))
+			return
+		}
+	}
+}
+
 func setCondition(to Setter, conditions []metav1.Condition, condition metav1.Condition) {
 	for i := range conditions {
 		if conditions[i].Type == string(condition.Type) {
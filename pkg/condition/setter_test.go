@@ -0,0 +1,78 @@
+/*
+Copyright 2025 OneX Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package condition
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDelete(t *testing.T) {
+	t.Run("removes a present condition", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &fakeObject{}
+		SetTrue(obj, MinersCreatedCondition)
+		SetFalse(obj, ResizedCondition, CreatingReason, "Creating miners")
+		SetTrue(obj, MinersReadyCondition)
+
+		Delete(obj, ResizedCondition)
+
+		g.Expect(Has(obj, ResizedCondition)).To(BeFalse())
+		g.Expect(obj.GetConditions()).To(HaveLen(2))
+		g.Expect(IsTrue(obj, MinersCreatedCondition)).To(BeTrue())
+		g.Expect(IsTrue(obj, MinersReadyCondition)).To(BeTrue())
+	})
+
+	t.Run("is a no-op for an absent condition", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &fakeObject{}
+		SetTrue(obj, MinersCreatedCondition)
+
+		Delete(obj, ResizedCondition)
+
+		g.Expect(obj.GetConditions()).To(HaveLen(1))
+		g.Expect(IsTrue(obj, MinersCreatedCondition)).To(BeTrue())
+	})
+}
+
+func TestMarkFalsef(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &fakeObject{}
+	MarkFalsef(obj, ChainReadyCondition, WaitingForChainReason, "Waiting for Chain %q (%d attempts)", "mainnet", 3)
+
+	c := Get(obj, ChainReadyCondition)
+	g.Expect(c).NotTo(BeNil())
+	g.Expect(c.Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(c.Reason).To(Equal(string(WaitingForChainReason)))
+	g.Expect(c.Message).To(Equal(`Waiting for Chain "mainnet" (3 attempts)`))
+}
+
+func TestMarkTrue(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &fakeObject{}
+	MarkFalsef(obj, ChainReadyCondition, WaitingForChainReason, "waiting")
+	MarkTrue(obj, ChainReadyCondition)
+
+	g.Expect(IsTrue(obj, ChainReadyCondition)).To(BeTrue())
+	g.Expect(obj.GetConditions()).To(HaveLen(1))
+}
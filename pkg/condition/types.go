@@ -49,6 +49,19 @@ const (
 
 	// ConfigMapsCreatedCondition indicates that configmaps have been created.
 	ConfigMapsCreatedCondition ConditionType = "ConfigMapsCreated"
+
+	// ConfigMapUpToDateCondition indicates that the content of a managed configmap matches the desired content.
+	ConfigMapUpToDateCondition ConditionType = "ConfigMapUpToDate"
+
+	// ChainReadyCondition indicates that the chain referenced by a miner set is ready.
+	ChainReadyCondition ConditionType = "ChainReady"
+
+	// PausedCondition indicates that the reconciliation of a resource is paused.
+	PausedCondition ConditionType = "Paused"
+
+	// PodNameTruncatedCondition indicates that the pod of a miner is not named after the miner
+	// because the miner name is too long.
+	PodNameTruncatedCondition ConditionType = "PodNameTruncated"
 )
 
 // ConditionReason is the reason for the condition's last transition.
@@ -64,6 +77,9 @@ const (
 	// DeletingReason is the reason when resources are being deleted.
 	DeletingReason ConditionReason = "Deleting"
 
+	// DrainingReason is the reason when resources are draining before being deleted.
+	DrainingReason ConditionReason = "Draining"
+
 	// DeletedReason is the reason when resources are deleted.
 	DeletedReason ConditionReason = "Deleted"
 
@@ -82,15 +98,78 @@ const (
 	// PodNotFoundReason is the reason when pod is not found.
 	PodNotFoundReason ConditionReason = "PodNotFound"
 
+	// ConfigMapNotFoundReason is the reason when a referenced configmap is not found.
+	ConfigMapNotFoundReason ConditionReason = "ConfigMapNotFound"
+
+	// ConfigMapConflictReason is the reason when a configmap carrying the label of a resource is controlled by another object.
+	ConfigMapConflictReason ConditionReason = "ConfigMapConflict"
+
+	// ConfigMapDriftedReason is the reason when a managed configmap diverged from its desired content.
+	ConfigMapDriftedReason ConditionReason = "ConfigMapDrifted"
+
+	// MinerConflictReason is the reason when a miner the controller needs to create already
+	// exists and is managed by someone else.
+	MinerConflictReason ConditionReason = "MinerConflict"
+
+	// PodConflictReason is the reason when a pod the controller needs to create already
+	// exists and is controlled by another object.
+	PodConflictReason ConditionReason = "PodConflict"
+
+	// WaitingForAddressReason is the reason when a ready pod has no IP address yet.
+	WaitingForAddressReason ConditionReason = "WaitingForAddress"
+
+	// ImagePullBackOffReason is the reason when the pod image cannot be pulled.
+	ImagePullBackOffReason ConditionReason = "ImagePullBackOff"
+
+	// ContainersNotReadyReason is the reason when some containers of the pod are not ready.
+	ContainersNotReadyReason ConditionReason = "ContainersNotReady"
+
+	// UnschedulableReason is the reason when the pod cannot be scheduled onto a node.
+	UnschedulableReason ConditionReason = "Unschedulable"
+
+	// PodCompletedReason is the reason when the pod ran to completion and is not running anymore.
+	PodCompletedReason ConditionReason = "PodCompleted"
+
+	// PodNameTruncatedReason is the reason when a pod name is truncated from the name of its owner.
+	PodNameTruncatedReason ConditionReason = "PodNameTruncated"
+
+	// ConfigChangedReason is the reason when the pod is recreated after a configuration change.
+	ConfigChangedReason ConditionReason = "ConfigChanged"
+
+	// RestartPolicyChangedReason is the reason when the pod is recreated with a new restart policy.
+	RestartPolicyChangedReason ConditionReason = "RestartPolicyChanged"
+
 	// PodConditionsFailedReason is the reason when pod conditions failed.
 	PodConditionsFailedReason ConditionReason = "PodConditionsFailed"
 
 	// InvalidConfigurationReason is the reason when configuration is invalid.
 	InvalidConfigurationReason ConditionReason = "InvalidConfiguration"
 
+	// SelectorInvalidReason is the reason when a label selector is invalid.
+	SelectorInvalidReason ConditionReason = "SelectorInvalid"
+
 	// MinerCreationFailedReason is the reason when miner creation failed.
 	MinerCreationFailedReason ConditionReason = "MinerCreationFailed"
 
 	// MinerDeletionFailedReason is the reason when miner deletion failed.
 	MinerDeletionFailedReason ConditionReason = "MinerDeletionFailed"
+
+	// NamespaceTerminatingReason is the reason when resources cannot be created because their namespace is terminating.
+	NamespaceTerminatingReason ConditionReason = "NamespaceTerminating"
+
+	// DisruptionBudgetBlockedReason is the reason when a PodDisruptionBudget blocks a scale down.
+	DisruptionBudgetBlockedReason ConditionReason = "DisruptionBudgetBlocked"
+
+	// WaitingForConfigMapReason is the reason when waiting for a referenced configmap to exist.
+	WaitingForConfigMapReason ConditionReason = "WaitingForConfigMap"
+
+	// WaitingForChainReason is the reason when waiting for the chain to become ready.
+	WaitingForChainReason ConditionReason = "WaitingForChain"
+
+	// ChainNotFoundReason is the reason when the chain referenced by a miner does not exist.
+	ChainNotFoundReason ConditionReason = "ChainNotFound"
+
+	// WaitingForMinerDeletionReason is the reason when waiting for a terminating miner of the
+	// same name to be deleted before creating a miner.
+	WaitingForMinerDeletionReason ConditionReason = "WaitingForMinerDeletion"
 )